@@ -0,0 +1,111 @@
+package main
+
+import "github.com/charmbracelet/lipgloss"
+
+// ActiveJobEntry is one row of the active section: a system job with the
+// operation that submitted it (if it came from this app), an operation
+// that isn't in the system queue, or the summary of collapsed completed
+// operations (Completed > 0, Job and Op nil).
+type ActiveJobEntry struct {
+	Job       *PrintJob
+	Op        *PrintOperation
+	Completed int
+}
+
+// buildActiveJobList returns the active section's rows in display order.
+// Rendering, cursor bounds and the row actions all index into this list, so
+// the row under the cursor is always the one acted on.
+//
+// System jobs come first (pinned ones are already sorted to the front of
+// m.jobs), each merged with its operation by system job ID. Operations
+// follow unless their job is listed, they were sent (the system queue
+// shows them) or they were canceled. With problemsOnly set, only failed
+// and canceled operations are listed. With collapseCompleted set, completed
+// operations are counted in a summary row at the end instead.
+func (m model) buildActiveJobList() []ActiveJobEntry {
+	var entries []ActiveJobEntry
+	if m.problemsOnly {
+		for _, i := range m.problemOps() {
+			entries = append(entries, ActiveJobEntry{Op: &m.printOps[i]})
+		}
+		return entries
+	}
+
+	listed := make(map[string]bool, len(m.jobs))
+	for i := range m.jobs {
+		entry := ActiveJobEntry{Job: &m.jobs[i]}
+		for j := range m.printOps {
+			if m.printOps[j].SystemJobID == m.jobs[i].ID {
+				entry.Op = &m.printOps[j]
+				break
+			}
+		}
+		listed[m.jobs[i].ID] = true
+		entries = append(entries, entry)
+	}
+
+	completed := 0
+	for i := range m.printOps {
+		op := &m.printOps[i]
+		if (op.SystemJobID != "" && listed[op.SystemJobID]) || op.Status == StatusSent || op.Status == StatusCanceled {
+			continue
+		}
+		if m.collapseCompleted && op.Status == StatusCompleted {
+			completed++
+			continue
+		}
+		entries = append(entries, ActiveJobEntry{Op: op})
+	}
+	if completed > 0 {
+		entries = append(entries, ActiveJobEntry{Completed: completed})
+	}
+	return entries
+}
+
+// activeIndexOfOp returns the active-section row of the operation with id, or -1
+func (m model) activeIndexOfOp(id string) int {
+	for i, entry := range m.buildActiveJobList() {
+		if entry.Op != nil && entry.Op.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// onCompletedSummary reports whether the active cursor is on the folded
+// completed operations row
+func (m model) onCompletedSummary() bool {
+	entries := m.buildActiveJobList()
+	return m.activeCursor >= 0 && m.activeCursor < len(entries) && entries[m.activeCursor].Completed > 0
+}
+
+// clearCompleted removes every completed operation from the list
+func (m *model) clearCompleted() {
+	kept := m.printOps[:0]
+	for _, op := range m.printOps {
+		if op.Status != StatusCompleted {
+			kept = append(kept, op)
+		}
+	}
+	m.printOps = kept
+	m.clampCursors()
+}
+
+// opStatusSymbol returns the row symbol and style for an operation's status
+func opStatusSymbol(status PrintStatus) (string, lipgloss.Style) {
+	switch status {
+	case StatusPending:
+		return "⏳", dimStyle
+	case StatusSending:
+		return "📤", normalStyle
+	case StatusFailed:
+		return "✗", errorStyle
+	case StatusCanceled:
+		return "⊘", dimStyle
+	case StatusCompleted:
+		return "✓", allDoneStyle
+	case StatusSafeMode:
+		return "◌", emptyFileStyle
+	}
+	return "●", normalStyle
+}
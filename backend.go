@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// PrintBackend talks to the print system: submitting, listing and canceling jobs
+type PrintBackend interface {
+	// Submit sends a file to the printer and returns the system job ID ("" if unknown)
+	Submit(filePath string, opts PrintOptions) (string, error)
+	// ListJobs returns the queued jobs; an error means the queue couldn't
+	// be read, not that it's empty
+	ListJobs() ([]PrintJob, error)
+	Cancel(jobID string) error
+	ListPrinters() []PrinterInfo
+}
+
+// backend is the print backend selected at startup
+var backend PrintBackend = cupsBackend{}
+
+// selectBackend picks the backend from the config; the CLI backend is the
+// default, or the print spooler on Windows where the CUPS tools don't exist
+func selectBackend(cfg Config) PrintBackend {
+	var native PrintBackend = cupsBackend{}
+	if runtime.GOOS == "windows" {
+		native = windowsBackend{}
+	}
+	switch cfg.Backend {
+	case "ipp":
+		return newIPPBackend(cfg.IPPServer, native)
+	default:
+		return native
+	}
+}
+
+// cupsBackend drives CUPS through its command line tools (lp, lpq, lpstat, cancel)
+type cupsBackend struct{}
+
+// Submit runs lp with -t to set the job title (filename)
+func (cupsBackend) Submit(filePath string, opts PrintOptions) (string, error) {
+	// Options add -d for the printer and -n for number of copies
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	argv := lpCommand(filePath, opts)
+	stdout, stderr, err := runner.Run(ctx, argv[0], argv[1:]...)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("print command timed out after 10 seconds")
+		}
+		return "", fmt.Errorf("failed to print: %v - %s", err, stderr)
+	}
+
+	// Parse job ID from lp output: "request id is PRINTER-123 (1 file(s))"
+	return parseJobIDFromLpOutput(string(stdout)), nil
+}
+
+// lpCommand returns the full lp invocation, program name first, that prints
+// filePath with opts. Submit runs exactly this; it is also shown to the user.
+func lpCommand(filePath string, opts PrintOptions) []string {
+	argv := append([]string{"lp"}, opts.lpArgs()...)
+	return append(argv, "-t", filepath.Base(filePath), filePath)
+}
+
+func (cupsBackend) ListJobs() ([]PrintJob, error) {
+	return getSystemPrintJobs()
+}
+
+func (cupsBackend) Cancel(jobID string) error {
+	return cancelPrintJob(jobID)
+}
+
+func (cupsBackend) ListPrinters() []PrinterInfo {
+	return getAvailablePrinters()
+}
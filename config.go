@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds user settings loaded from ~/.config/printer/config.toml
+type Config struct {
+	SkipEmptyFiles bool // skip_empty_files: leave 0-byte files out when printing
+	Offline        bool // offline: never poll jobs/printers, hide the active section
+	MaxPrintOps    int  // max_print_operations: finished operations kept in the list
+	HistoryLimit   int  // history_limit: sent prints kept in the history (0 keeps all)
+	PersistStaging bool // persist_staging: keep the staged list between sessions
+	WrapHelpBar    bool // wrap_help_bar: wrap the help bar onto two lines instead of truncating
+	WrapProblems   bool // wrap_problem_navigation: [ and ] wrap around the ends of the list
+	Mouse          bool // mouse: click and scroll lists (off keeps the terminal's own text selection)
+	ShowScrollbar  bool // show_scrollbar: draw list scrollbars (B toggles them for the session)
+
+	// TrackCompletion keeps watching sent jobs until they leave the system
+	// queue and marks them completed (track_completion)
+	TrackCompletion bool
+
+	// ConfirmOverFiles and ConfirmOverSize ask before printing a batch with
+	// more staged files or more bytes than this (confirm_over_files, default
+	// 5, and confirm_over_mb; 0 never asks)
+	ConfirmOverFiles int
+	ConfirmOverSize  int64
+
+	// RecursiveStageLimit caps how many files R stages from one directory
+	// tree (recursive_stage_limit)
+	RecursiveStageLimit int
+
+	// BlockWritingFiles refuses to stage files that look like they are still
+	// being written, e.g. a download in progress (block_files_being_written)
+	BlockWritingFiles bool
+
+	// BellOnFailure rings the terminal bell and FlashOnFailure briefly flashes
+	// the screen when a print fails (bell_on_failure, flash_on_failure)
+	BellOnFailure  bool
+	FlashOnFailure bool
+
+	// ClearSentAfter removes sent operations from the list after this delay
+	// (clear_sent_after_seconds, 0 keeps them until the job leaves the queue)
+	ClearSentAfter time.Duration
+
+	// RecentPrintWindow flags staged files printed within this window
+	// (recent_print_window_minutes, 0 disables)
+	RecentPrintWindow time.Duration
+
+	// Backend selects how to talk to CUPS: "cli" (lp/lpq, default) or "ipp".
+	// IPPServer is the scheduler the IPP backend connects to (ipp_server).
+	Backend   string
+	IPPServer string
+
+	// StartupPane is the pane focused at launch: "queue" (default) or "files".
+	// StartupFocus picks the files pane focus: "input" (default) or "list".
+	StartupPane  string
+	StartupFocus string
+
+	// StagedFormat is the template for staged rows (staged_format), e.g.
+	// "{name} ({size})"; see stagedPlaceholders for the fields
+	StagedFormat string
+
+	// PrintableExts are the extensions offered for printing, lowercase with
+	// the dot (printable_extensions replaces the built-in list,
+	// extra_printable_extensions adds to it)
+	PrintableExts []string
+
+	// DirectoryDefaults are print options applied when staging from a directory,
+	// keyed by path prefix: [directory."~/Scans"] with copies, printer,
+	// duplex, color, pages and options keys
+	DirectoryDefaults map[string]PrintOptions
+}
+
+// config is the active configuration, loaded once at startup
+var config = defaultConfig()
+
+// configErr is the error from loading the config file, shown once in the UI
+var configErr error
+
+func defaultConfig() Config {
+	return Config{
+		SkipEmptyFiles:      false,
+		MaxPrintOps:         200,
+		HistoryLimit:        1000,
+		RecursiveStageLimit: 500,
+		ConfirmOverFiles:    5,
+		BlockWritingFiles:   true,
+		PersistStaging:      true,
+		WrapProblems:        true,
+		Mouse:               true,
+		ShowScrollbar:       true,
+		RecentPrintWindow:   time.Hour,
+		Backend:             "cli",
+		IPPServer:           "http://localhost:631",
+		StartupPane:         "queue",
+		StartupFocus:        "input",
+		StagedFormat:        defaultStagedFormat,
+		PrintableExts:       append([]string(nil), defaultPrintableExts...),
+		DirectoryDefaults:   make(map[string]PrintOptions),
+	}
+}
+
+// configFlag is the --config flag value, "" when not given
+var configFlag string
+
+// explicitConfigPath returns the config file chosen with --config or
+// $PRINTER_CONFIG, or "" to use the default location
+func explicitConfigPath() string {
+	if configFlag != "" {
+		return expandHome(configFlag)
+	}
+	if env := os.Getenv("PRINTER_CONFIG"); env != "" {
+		return expandHome(env)
+	}
+	return ""
+}
+
+// configPath returns the config file location: --config, $PRINTER_CONFIG,
+// or $XDG_CONFIG_HOME/printer/config.toml
+func configPath() (string, error) {
+	if path := explicitConfigPath(); path != "" {
+		return path, nil
+	}
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "printer", "config.toml"), nil
+}
+
+// loadConfig reads the config file over the defaults. A missing file is not an
+// error unless it was chosen explicitly.
+func loadConfig() (Config, error) {
+	cfg := defaultConfig()
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && explicitConfigPath() == "" {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	values, err := parseConfig(string(data))
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := cfg.apply(values); err != nil {
+		return cfg, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// parseConfig parses the small TOML subset we use: comments, [tables] and
+// key = value lines. Keys inside a table are returned as "table.key".
+func parseConfig(data string) (map[string]string, error) {
+	values := make(map[string]string)
+	table := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			// [directory."~/Scans"] -> directory.~/Scans
+			table = strings.ReplaceAll(strings.TrimSpace(line[1:len(line)-1]), `"`, "")
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq == -1 {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNum)
+		}
+		key := strings.Trim(strings.TrimSpace(line[:eq]), `"`)
+		if table != "" {
+			key = table + "." + key
+		}
+		values[key] = strings.TrimSpace(line[eq+1:])
+	}
+	return values, scanner.Err()
+}
+
+// stripComment removes a trailing # comment that isn't inside a string
+func stripComment(line string) string {
+	inString := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inString = !inString
+		case '#':
+			if !inString {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// apply sets known keys from parsed values; unknown keys are ignored
+func (c *Config) apply(values map[string]string) error {
+	// Keys come in map order, so the extension lists are combined at the end
+	var baseExts, extraExts []string
+	for key, raw := range values {
+		var err error
+		switch {
+		case key == "skip_empty_files":
+			c.SkipEmptyFiles, err = strconv.ParseBool(raw)
+		case key == "offline":
+			c.Offline, err = strconv.ParseBool(raw)
+		case key == "mouse":
+			c.Mouse, err = strconv.ParseBool(raw)
+		case key == "show_scrollbar":
+			c.ShowScrollbar, err = strconv.ParseBool(raw)
+		case key == "wrap_problem_navigation":
+			c.WrapProblems, err = strconv.ParseBool(raw)
+		case key == "bell_on_failure":
+			c.BellOnFailure, err = strconv.ParseBool(raw)
+		case key == "flash_on_failure":
+			c.FlashOnFailure, err = strconv.ParseBool(raw)
+		case key == "printable_extensions":
+			baseExts, err = parseExtensions(raw)
+		case key == "extra_printable_extensions":
+			extraExts, err = parseExtensions(raw)
+		case key == "track_completion":
+			c.TrackCompletion, err = strconv.ParseBool(raw)
+		case key == "wrap_help_bar":
+			c.WrapHelpBar, err = strconv.ParseBool(raw)
+		case key == "persist_staging":
+			c.PersistStaging, err = strconv.ParseBool(raw)
+		case key == "block_files_being_written":
+			c.BlockWritingFiles, err = strconv.ParseBool(raw)
+		case key == "recursive_stage_limit":
+			c.RecursiveStageLimit, err = strconv.Atoi(raw)
+		case key == "max_print_operations":
+			c.MaxPrintOps, err = strconv.Atoi(raw)
+		case key == "history_limit":
+			c.HistoryLimit, err = strconv.Atoi(raw)
+		case key == "confirm_over_files":
+			c.ConfirmOverFiles, err = strconv.Atoi(raw)
+		case key == "confirm_over_mb":
+			var mb int64
+			mb, err = strconv.ParseInt(raw, 10, 64)
+			c.ConfirmOverSize = mb * 1024 * 1024
+		case key == "clear_sent_after_seconds":
+			var seconds int
+			seconds, err = strconv.Atoi(raw)
+			c.ClearSentAfter = time.Duration(seconds) * time.Second
+		case key == "recent_print_window_minutes":
+			var minutes int
+			minutes, err = strconv.Atoi(raw)
+			c.RecentPrintWindow = time.Duration(minutes) * time.Minute
+		case key == "backend":
+			c.Backend, err = strconv.Unquote(raw)
+			if err == nil && c.Backend != "cli" && c.Backend != "ipp" {
+				err = fmt.Errorf("unknown backend %q (expected \"cli\" or \"ipp\")", c.Backend)
+			}
+		case key == "ipp_server":
+			c.IPPServer, err = strconv.Unquote(raw)
+		case key == "startup_pane":
+			c.StartupPane, err = strconv.Unquote(raw)
+			if err == nil && c.StartupPane != "queue" && c.StartupPane != "files" {
+				err = fmt.Errorf("unknown pane %q (expected \"queue\" or \"files\")", c.StartupPane)
+			}
+		case key == "startup_focus":
+			c.StartupFocus, err = strconv.Unquote(raw)
+			if err == nil && c.StartupFocus != "input" && c.StartupFocus != "list" {
+				err = fmt.Errorf("unknown focus %q (expected \"input\" or \"list\")", c.StartupFocus)
+			}
+		case key == "staged_format":
+			// An invalid template keeps the default
+			var format string
+			if format, err = parseStagedFormat(raw); err == nil {
+				c.StagedFormat = format
+			}
+		case strings.HasPrefix(key, "directory."):
+			err = c.applyDirectoryDefault(strings.TrimPrefix(key, "directory."), raw)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	if baseExts != nil {
+		c.PrintableExts = baseExts
+	}
+	c.PrintableExts = append(c.PrintableExts, extraExts...)
+	return nil
+}
+
+// parseExtensions parses a list of extensions, [".odt", "svg"], into
+// lowercase extensions with a leading dot
+func parseExtensions(raw string) ([]string, error) {
+	if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+		return nil, fmt.Errorf("expected a list like [\".odt\", \".rtf\"]")
+	}
+	exts := []string{}
+	for _, item := range strings.Split(raw[1:len(raw)-1], ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		ext, err := strconv.Unquote(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a quoted string", item)
+		}
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" || ext == "." {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
+
+// applyDirectoryDefault sets one option of a [directory."path"] table.
+// key is "path.option"; the option name is after the last dot.
+func (c *Config) applyDirectoryDefault(key, raw string) error {
+	dot := strings.LastIndex(key, ".")
+	if dot == -1 {
+		return fmt.Errorf("expected an option name")
+	}
+	dir := expandHome(key[:dot])
+	opts := c.DirectoryDefaults[dir]
+
+	option := key[dot+1:]
+	if option == "copies" {
+		copies, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		opts.Copies = copies
+		c.DirectoryDefaults[dir] = opts
+		return nil
+	}
+
+	// Everything else is a quoted string
+	value, err := strconv.Unquote(raw)
+	if err != nil {
+		return err
+	}
+	switch option {
+	case "printer":
+		opts.Printer = value
+	case "duplex":
+		switch value {
+		case "none":
+			opts.Duplex = DuplexNone
+		case "long":
+			opts.Duplex = DuplexLong
+		case "short":
+			opts.Duplex = DuplexShort
+		default:
+			return fmt.Errorf("duplex must be \"none\", \"long\" or \"short\"")
+		}
+	case "color":
+		switch value {
+		case "auto":
+			opts.Color = ColorAuto
+		case "color":
+			opts.Color = ColorColor
+		case "gray":
+			opts.Color = ColorGray
+		default:
+			return fmt.Errorf("color must be \"auto\", \"color\" or \"gray\"")
+		}
+	case "pages":
+		if opts.Pages, err = parsePageRanges(value); err != nil {
+			return err
+		}
+	case "options":
+		if opts.Extra, err = parseExtraOptions(value); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown option %q", option)
+	}
+	c.DirectoryDefaults[dir] = opts
+	return nil
+}
+
+// directoryDefaults returns the defaults for the longest configured directory
+// prefix containing dir
+func (c Config) directoryDefaults(dir string) (string, PrintOptions, bool) {
+	best := ""
+	for prefix := range c.DirectoryDefaults {
+		if (dir == prefix || strings.HasPrefix(dir, prefix+string(filepath.Separator))) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return "", PrintOptions{}, false
+	}
+	return best, c.DirectoryDefaults[best], true
+}
+
+// expandHome expands a leading ~ to the user's home directory and cleans the path
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDirectoryDefaultsOptions(t *testing.T) {
+	values, err := parseConfig(`
+[directory."/srv/scans"]
+copies = 2
+printer = "Office"
+duplex = "long"
+color = "gray"
+pages = "1-2"
+options = "media=A4 number-up=2"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := defaultConfig()
+	if err := c.apply(values); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+
+	want := PrintOptions{Printer: "Office", Copies: 2, Duplex: DuplexLong, Color: ColorGray,
+		Pages: "1-2", Extra: []string{"media=A4", "number-up=2"}}
+	dir, got, ok := c.directoryDefaults("/srv/scans/2026")
+	if !ok || dir != "/srv/scans" || !reflect.DeepEqual(got, want) {
+		t.Errorf("directoryDefaults() = %q, %+v, %v; want /srv/scans, %+v", dir, got, ok, want)
+	}
+}
+
+func TestDirectoryDefaultsRejectBadValues(t *testing.T) {
+	for _, line := range []string{
+		`duplex = "sideways"`,
+		`color = "sepia"`,
+		`pages = "5-2"`,
+		`options = "media"`,
+		`duplex = long`,
+		`staple = "yes"`,
+	} {
+		values, err := parseConfig("[directory.\"/srv/scans\"]\n" + line + "\n")
+		if err != nil {
+			continue
+		}
+		c := defaultConfig()
+		if err := c.apply(values); err == nil {
+			t.Errorf("apply(%s) accepted a bad value", line)
+		}
+	}
+}
+
+func TestStageFileAppliesDirectoryDefaults(t *testing.T) {
+	saved := config.DirectoryDefaults
+	config.DirectoryDefaults = map[string]PrintOptions{
+		"/srv/scans": {Duplex: DuplexShort, Color: ColorGray, Pages: "1", Extra: []string{"media=A4"}},
+	}
+	t.Cleanup(func() { config.DirectoryDefaults = saved })
+
+	m := newTestModel(t)
+	m.stageFile("a.pdf", "/srv/scans/a.pdf", "/srv/scans", 10)
+	file := m.stagedFiles[len(m.stagedFiles)-1]
+	if file.Duplex != DuplexShort || file.Color != ColorGray || file.Pages != "1" || len(file.ExtraOptions) != 1 {
+		t.Errorf("staged file = %+v, want the directory's duplex, color, pages and options", file)
+	}
+}
+
+func TestShowScrollbarSetsStartupState(t *testing.T) {
+	values, err := parseConfig("show_scrollbar = false\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := defaultConfig()
+	if !c.ShowScrollbar {
+		t.Fatal("scrollbars are hidden by default")
+	}
+	if err := c.apply(values); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+
+	saved := config
+	config = c
+	t.Cleanup(func() { config = saved })
+	if m := newTestModel(t); !m.hideScrollbar {
+		t.Error("show_scrollbar = false still started with scrollbars shown")
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Contact sheet page: A4 at 150 DPI keeps the temp file small but sharp enough for thumbnails
+const (
+	contactSheetWidth   = 1240
+	contactSheetHeight  = 1754
+	contactSheetMargin  = 40
+	contactSheetSpacing = 20
+)
+
+// contactSheetGrid is the thumbnail grid as columns x rows (set with -contact-grid)
+var contactSheetGrid = struct{ Cols, Rows int }{Cols: 4, Rows: 5}
+
+// Image extensions that can be decoded into a contact sheet
+var contactSheetExts = []string{".jpg", ".jpeg", ".png", ".gif"}
+
+// parseGrid parses a "COLSxROWS" grid spec such as "4x5"
+func parseGrid(spec string) (cols, rows int, err error) {
+	parts := strings.Split(strings.ToLower(spec), "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid grid %q, expected COLSxROWS", spec)
+	}
+	cols, err = strconv.Atoi(parts[0])
+	if err != nil || cols < 1 {
+		return 0, 0, fmt.Errorf("invalid grid columns in %q", spec)
+	}
+	rows, err = strconv.Atoi(parts[1])
+	if err != nil || rows < 1 {
+		return 0, 0, fmt.Errorf("invalid grid rows in %q", spec)
+	}
+	return cols, rows, nil
+}
+
+// isContactSheetImage reports whether a file can be placed on a contact sheet
+func isContactSheetImage(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, imgExt := range contactSheetExts {
+		if ext == imgExt {
+			return true
+		}
+	}
+	return false
+}
+
+// buildContactSheet lays out images in a grid on a white page and writes it as a PNG temp file
+func buildContactSheet(paths []string, cols, rows int) (string, error) {
+	page := image.NewRGBA(image.Rect(0, 0, contactSheetWidth, contactSheetHeight))
+	draw.Draw(page, page.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+
+	cellWidth := (contactSheetWidth - 2*contactSheetMargin - (cols-1)*contactSheetSpacing) / cols
+	cellHeight := (contactSheetHeight - 2*contactSheetMargin - (rows-1)*contactSheetSpacing) / rows
+	if cellWidth < 1 || cellHeight < 1 {
+		return "", fmt.Errorf("grid %dx%d is too dense for one page", cols, rows)
+	}
+
+	for i, path := range paths {
+		if i >= cols*rows {
+			break
+		}
+		img, err := decodeImage(path)
+		if err != nil {
+			return "", err
+		}
+
+		col, row := i%cols, i/cols
+		cell := image.Rect(0, 0, cellWidth, cellHeight).Add(image.Pt(
+			contactSheetMargin+col*(cellWidth+contactSheetSpacing),
+			contactSheetMargin+row*(cellHeight+contactSheetSpacing),
+		))
+		drawThumbnail(page, cell, img)
+	}
+
+	out, err := os.CreateTemp("", "printer-contact-*.png")
+	if err != nil {
+		return "", fmt.Errorf("failed to create contact sheet: %v", err)
+	}
+	defer out.Close()
+
+	if err := png.Encode(out, page); err != nil {
+		os.Remove(out.Name())
+		return "", fmt.Errorf("failed to write contact sheet: %v", err)
+	}
+	return out.Name(), nil
+}
+
+// decodeImage opens and decodes a single image file
+func decodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %v", filepath.Base(path), err)
+	}
+	return img, nil
+}
+
+// drawThumbnail scales img to fit inside cell (keeping aspect ratio) and centers it
+func drawThumbnail(dst *image.RGBA, cell image.Rectangle, img image.Image) {
+	src := img.Bounds()
+	if src.Dx() == 0 || src.Dy() == 0 {
+		return
+	}
+
+	scale := float64(cell.Dx()) / float64(src.Dx())
+	if s := float64(cell.Dy()) / float64(src.Dy()); s < scale {
+		scale = s
+	}
+	w := int(float64(src.Dx()) * scale)
+	h := int(float64(src.Dy()) * scale)
+	offX := cell.Min.X + (cell.Dx()-w)/2
+	offY := cell.Min.Y + (cell.Dy()-h)/2
+
+	// Nearest-neighbour sampling is plenty for thumbnails
+	for y := 0; y < h; y++ {
+		sy := src.Min.Y + int(float64(y)/scale)
+		for x := 0; x < w; x++ {
+			sx := src.Min.X + int(float64(x)/scale)
+			dst.Set(offX+x, offY+y, img.At(sx, sy))
+		}
+	}
+}
+
+// contactSheetCmd builds the contact sheet in the background and submits it as one job
+func contactSheetCmd(opID, printer string, paths []string) tea.Cmd {
+	return func() tea.Msg {
+		sheetPath, err := buildContactSheet(paths, contactSheetGrid.Cols, contactSheetGrid.Rows)
+		if err != nil {
+			return PrintStatusMsg{
+				FileID: opID,
+				Status: StatusFailed,
+				Error:  err,
+			}
+		}
+		// lp copies the file into the spool, so the temp file can go once submitted
+		defer os.Remove(sheetPath)
+
+		return submitPrintJobCmd(opID, sheetPath, PrintOptions{Printer: printer, Copies: 1})()
+	}
+}
+
+// printContactSheet submits all staged images as a single contact sheet operation
+func (m *model) printContactSheet() tea.Cmd {
+	if len(m.stagedFiles) == 0 {
+		return nil
+	}
+
+	var paths []string
+	for _, file := range m.stagedFiles {
+		if !isContactSheetImage(file.Path) {
+			m.errorMsg = fmt.Sprintf("Contact sheet needs only images: %s is not one", file.Name)
+			return nil
+		}
+		paths = append(paths, file.Path)
+	}
+	if capacity := contactSheetGrid.Cols * contactSheetGrid.Rows; len(paths) > capacity {
+		m.errorMsg = fmt.Sprintf("Contact sheet fits %d images, %d staged", capacity, len(paths))
+		return nil
+	}
+
+	opID := fmt.Sprintf("contact-sheet-%d", time.Now().UnixNano())
+	printer := m.destination("")
+	m.printOps = append(m.printOps, PrintOperation{
+		ID:        opID,
+		FileName:  fmt.Sprintf("Contact sheet (%d images)", len(paths)),
+		Printer:   printer,
+		Status:    StatusSending,
+		StartedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	for _, file := range m.stagedFiles {
+		delete(m.markedFiles, file.Path)
+	}
+	m.stagedFiles = []StagedFile{}
+	m.stagedCursor = 0
+	m.queueSection = SectionActive
+	m.activeCursor = m.getActualJobCount() - 1
+
+	return m.trackCmd(contactSheetCmd(opID, printer, paths))
+}
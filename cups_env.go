@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lpoptions holds the user's ~/.cups/lpoptions: the default destination and
+// per-printer default options, as set with `lpoptions -d` / `lpoptions -p -o`.
+// Instances ("Office/draft") are kept under their full name, so their
+// options only apply when printing to the instance.
+type lpoptions struct {
+	defaultDest string
+	options     map[string][]string // printer or printer/instance -> key=value options
+}
+
+// loadLPOptions reads ~/.cups/lpoptions; a missing file yields no defaults
+func loadLPOptions() lpoptions {
+	lp := lpoptions{options: make(map[string][]string)}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return lp
+	}
+	f, err := os.Open(filepath.Join(home, ".cups", "lpoptions"))
+	if err != nil {
+		return lp
+	}
+	defer f.Close()
+
+	// "Default EPSON_ET_2810_Series media=A4 sides=two-sided-long-edge"
+	// "Dest Office/draft print-color-mode=monochrome"
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kind := strings.ToLower(fields[0])
+		if kind != "default" && kind != "dest" {
+			continue
+		}
+		name := fields[1]
+		if kind == "default" {
+			lp.defaultDest = name
+		}
+		for _, option := range fields[2:] {
+			if strings.Contains(option, "=") {
+				lp.options[name] = append(lp.options[name], option)
+			}
+		}
+	}
+	return lp
+}
+
+// cupsUserDefault returns the default destination the way lp resolves it:
+// $LPDEST, then $PRINTER, then the lpoptions Default. "" means the system default.
+func cupsUserDefault() string {
+	for _, env := range []string{"LPDEST", "PRINTER"} {
+		if dest := strings.TrimSpace(os.Getenv(env)); dest != "" {
+			return dest
+		}
+	}
+	return loadLPOptions().defaultDest
+}
+
+// queueName returns the CUPS queue of a destination, dropping any instance:
+// "Office/draft" prints on the Office queue
+func queueName(dest string) string {
+	queue, _, _ := strings.Cut(dest, "/")
+	return queue
+}
+
+// lpoptionsFor returns the user's lpoptions defaults for printer, leaving out
+// keys that the explicit options already set. An instance starts from its
+// queue's options and overrides them with its own, as lp does.
+func lpoptionsFor(printer string, explicit []string) []string {
+	set := make(map[string]bool)
+	for _, option := range explicit {
+		key, _, _ := strings.Cut(option, "=")
+		set[key] = true
+	}
+	lp := loadLPOptions()
+	sources := [][]string{lp.options[printer]}
+	if queue := queueName(printer); queue != printer {
+		sources = append(sources, lp.options[queue])
+	}
+	var options []string
+	for _, source := range sources {
+		for _, option := range source {
+			key, _, _ := strings.Cut(option, "=")
+			if !set[key] {
+				set[key] = true
+				options = append(options, option)
+			}
+		}
+	}
+	return options
+}
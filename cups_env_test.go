@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeLPOptions points HOME at a temporary directory holding an lpoptions file
+func writeLPOptions(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".cups"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".cups", "lpoptions"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLPOptionsKeepInstancesApart(t *testing.T) {
+	writeLPOptions(t, `Default Office media=A4 sides=two-sided-long-edge
+Dest Office/draft print-color-mode=monochrome sides=one-sided
+Dest Lab number-up=2
+`)
+
+	tests := []struct {
+		printer  string
+		explicit []string
+		want     []string
+	}{
+		{"Office", nil, []string{"media=A4", "sides=two-sided-long-edge"}},
+		{"Office/draft", nil, []string{"print-color-mode=monochrome", "sides=one-sided", "media=A4"}},
+		{"Office/draft", []string{"sides=two-sided-short-edge"}, []string{"print-color-mode=monochrome", "media=A4"}},
+		{"Lab", nil, []string{"number-up=2"}},
+		{"Other", nil, nil},
+	}
+	for _, tt := range tests {
+		if got := lpoptionsFor(tt.printer, tt.explicit); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lpoptionsFor(%q, %q) = %q, want %q", tt.printer, tt.explicit, got, tt.want)
+		}
+	}
+}
+
+func TestCupsUserDefaultInstance(t *testing.T) {
+	writeLPOptions(t, "Default Office/draft print-color-mode=monochrome\n")
+	t.Setenv("LPDEST", "")
+	t.Setenv("PRINTER", "")
+
+	if got := cupsUserDefault(); got != "Office/draft" {
+		t.Errorf("cupsUserDefault() = %q, want Office/draft", got)
+	}
+	if got := queueName("Office/draft"); got != "Office" {
+		t.Errorf("queueName(Office/draft) = %q, want Office", got)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dirCacheSize is how many recently listed directories are kept
+const dirCacheSize = 128
+
+// dirScanBatch is how many uncached directories one background scan reads
+const dirScanBatch = 16
+
+// dirListing is a directory's entries as of its modification time. Only
+// names are kept: writing into a file changes its size and time but not the
+// directory's, so those are read fresh whenever the listing is reused.
+type dirListing struct {
+	modTime  time.Time
+	entries  []cachedEntry
+	listedAt time.Time // For evicting the oldest listing
+	failed   bool      // Couldn't be read; counted as empty, not scanned again
+}
+
+// cachedEntry is what a listing keeps of each directory entry
+type cachedEntry struct {
+	name  string
+	isDir bool
+}
+
+// newDirListing keeps the names of a directory's entries
+func newDirListing(modTime time.Time, infos []os.FileInfo, err error) dirListing {
+	listing := dirListing{modTime: modTime, listedAt: time.Now(), failed: err != nil}
+	for _, info := range infos {
+		listing.entries = append(listing.entries, cachedEntry{name: info.Name(), isDir: info.IsDir()})
+	}
+	return listing
+}
+
+// dirsScannedMsg carries listings read in the background for the counts of
+// directories shown in the browser
+type dirsScannedMsg struct {
+	listings map[string]dirListing
+}
+
+// dirCache keeps recent directory listings so re-entering a directory
+// doesn't read it again. The model holds it by pointer so the views can use
+// it too.
+type dirCache struct {
+	listings map[string]dirListing
+}
+
+func newDirCache() *dirCache {
+	return &dirCache{listings: make(map[string]dirListing)}
+}
+
+// read returns dir's entries, listing the directory only when it isn't
+// cached or its modification time changed since it was cached. Cached
+// entries are stat'ed again so sizes and times are current.
+func (c *dirCache) read(dir string) ([]os.FileInfo, error) {
+	modTime := dirModTime(dir)
+	if listing, ok := c.listings[dir]; ok && !listing.failed && !modTime.IsZero() && listing.modTime.Equal(modTime) {
+		infos := make([]os.FileInfo, 0, len(listing.entries))
+		for _, entry := range listing.entries {
+			// Lstat like ReadDir; an entry gone since is simply left out
+			if info, err := os.Lstat(filepath.Join(dir, entry.name)); err == nil {
+				infos = append(infos, info)
+			}
+		}
+		return infos, nil
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		delete(c.listings, dir)
+		return nil, err
+	}
+	c.store(dir, newDirListing(modTime, infos, nil))
+	return infos, nil
+}
+
+// store caches a listing, evicting the oldest one when full
+func (c *dirCache) store(dir string, listing dirListing) {
+	if _, ok := c.listings[dir]; !ok && len(c.listings) >= dirCacheSize {
+		c.evictOldest()
+	}
+	c.listings[dir] = listing
+}
+
+// has reports whether dir has a listing, even a failed one
+func (c *dirCache) has(dir string) bool {
+	_, ok := c.listings[dir]
+	return ok
+}
+
+// invalidate forgets dir, e.g. when its files changed size without the
+// directory's modification time changing
+func (c *dirCache) invalidate(dir string) {
+	delete(c.listings, dir)
+}
+
+// printableCount returns how many printable files a cached directory holds,
+// without any I/O; ok is false when dir isn't cached
+func (c *dirCache) printableCount(dir string, showHidden bool) (count int, ok bool) {
+	listing, ok := c.listings[dir]
+	if !ok {
+		return 0, false
+	}
+	// A failed listing has no entries and so counts as empty
+	for _, entry := range listing.entries {
+		if !showHidden && strings.HasPrefix(entry.name, ".") {
+			continue
+		}
+		if !entry.isDir && isPrintableName(entry.name) {
+			count++
+		}
+	}
+	return count, true
+}
+
+// evictOldest drops the listing cached longest ago
+func (c *dirCache) evictOldest() {
+	oldest := ""
+	for dir, listing := range c.listings {
+		if oldest == "" || listing.listedAt.Before(c.listings[oldest].listedAt) {
+			oldest = dir
+		}
+	}
+	delete(c.listings, oldest)
+}
+
+// scanNearbyDirs lists, in the background, the uncached directories within a
+// screen of the file cursor so their printable counts can be shown. It's lazy
+// on purpose: far-away siblings in big trees are only read once scrolled to.
+func (m *model) scanNearbyDirs() tea.Cmd {
+	if m.dirScanning || (m.layoutMode == LayoutSingle && m.activePane != PaneFiles) {
+		return nil
+	}
+	var dirs []string
+	for i := max(0, m.fileCursor-m.height); i < min(len(m.files), m.fileCursor+m.height+1); i++ {
+		if file := m.files[i]; file.IsDir && !m.dirCache.has(file.Path) {
+			dirs = append(dirs, file.Path)
+			if len(dirs) == dirScanBatch {
+				break
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	m.dirScanning = true
+	return func() tea.Msg {
+		listings := make(map[string]dirListing, len(dirs))
+		for _, dir := range dirs {
+			modTime := dirModTime(dir)
+			infos, err := ioutil.ReadDir(dir)
+			listings[dir] = newDirListing(modTime, infos, err)
+		}
+		return dirsScannedMsg{listings: listings}
+	}
+}
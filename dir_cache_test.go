@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirCacheRereadsSizesOnReuse(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scan.pdf")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := newDirCache()
+	if infos, err := cache.read(dir); err != nil || len(infos) != 1 || infos[0].Size() != 0 {
+		t.Fatalf("first read = %v, %v; want scan.pdf of size 0", infos, err)
+	}
+
+	// Writing into an existing file leaves the directory's mtime alone
+	if err := os.WriteFile(path, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	infos, err := cache.read(dir)
+	if err != nil || len(infos) != 1 || infos[0].Size() != 8 {
+		t.Fatalf("cached read = %v, %v; want scan.pdf of size 8", infos, err)
+	}
+}
+
+func TestDirCachePrintableCount(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.pdf", "b.txt", ".hidden.pdf", "photo.bin"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub.pdf"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := newDirCache()
+	if _, ok := cache.printableCount(dir, false); ok {
+		t.Fatal("printableCount reported an uncached directory")
+	}
+	if _, err := cache.read(dir); err != nil {
+		t.Fatal(err)
+	}
+	want := 0
+	for _, name := range []string{"a.pdf", "b.txt"} {
+		if isPrintableName(name) {
+			want++
+		}
+	}
+	if got, ok := cache.printableCount(dir, false); !ok || got != want {
+		t.Errorf("printableCount(hidden off) = %d, %v; want %d", got, ok, want)
+	}
+	if got, _ := cache.printableCount(dir, true); got != want+1 {
+		t.Errorf("printableCount(hidden on) = %d, want %d", got, want+1)
+	}
+}
+
+func TestOfflineTickScansNearbyDirs(t *testing.T) {
+	saved := config.Offline
+	config.Offline = true
+	t.Cleanup(func() { config.Offline = saved })
+
+	fake := useFakeRunner(t)
+	m := newTestModel(t)
+	sub := filepath.Join(m.currentDir, "reports")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "a.pdf"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.loadDirectory()
+	m.activePane = PaneFiles
+	m.height = 40
+
+	updated, cmd := m.update(tickMsg{})
+	m = updated.(model)
+	if cmd == nil || m.inFlight != 0 {
+		t.Fatalf("offline tick: cmd %v, %d in flight; want a tick and no job refresh", cmd, m.inFlight)
+	}
+	if !m.dirScanning {
+		t.Fatal("offline tick didn't start scanning the nearby directory")
+	}
+
+	if m.scanNearbyDirs() != nil {
+		t.Error("a second scan started while one was running")
+	}
+
+	// Run the scan the tick started
+	m.dirScanning = false
+	updated, _ = m.update(m.scanNearbyDirs()())
+	m = updated.(model)
+	if count, ok := m.dirCache.printableCount(sub, false); !ok || count != 1 {
+		t.Errorf("printableCount(reports) = %d, %v; want 1", count, ok)
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("offline tick ran %q", fake.calls)
+	}
+}
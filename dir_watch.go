@@ -0,0 +1,124 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// dirReloadDelay lets a burst of changes (a scan being written, a copy of
+// many files) settle into a single reload
+const dirReloadDelay = 300 * time.Millisecond
+
+// dirChangedMsg reports that the watched directory's entries changed
+type dirChangedMsg struct{}
+
+// dirReloadMsg reloads the browsed directory unless a newer change superseded it
+type dirReloadMsg struct {
+	seq int
+}
+
+// newDirWatcher starts an fsnotify watcher, or returns nil when the platform
+// can't watch; the browser then notices changes on the regular tick instead
+func newDirWatcher() *fsnotify.Watcher {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil
+	}
+	return w
+}
+
+// waitForDirChange blocks until the watcher reports an entry being added,
+// removed or renamed. Watcher errors are ignored; the next event still arrives.
+func waitForDirChange(w *fsnotify.Watcher) tea.Cmd {
+	if w == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		for {
+			select {
+			case event, ok := <-w.Events:
+				if !ok {
+					return nil
+				}
+				if event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+					return dirChangedMsg{}
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// watchCurrentDir points the watcher at the browsed directory. Without a
+// watcher, or when the directory can't be watched, it remembers the
+// directory's modification time for pollCurrentDir instead.
+func (m *model) watchCurrentDir() {
+	if m.watchedDir == m.currentDir {
+		return
+	}
+	if m.dirWatcher != nil {
+		if m.watchedDir != "" {
+			_ = m.dirWatcher.Remove(m.watchedDir)
+		}
+		if m.dirWatcher.Add(m.currentDir) == nil {
+			m.watchedDir = m.currentDir
+			m.dirPolled = false
+			return
+		}
+	}
+	m.watchedDir = m.currentDir
+	m.dirPolled = true
+	m.dirModTime = dirModTime(m.currentDir)
+}
+
+// pollCurrentDir reloads the browser when the directory changed since the
+// last look; used only where fsnotify isn't watching it
+func (m *model) pollCurrentDir() {
+	if !m.dirPolled {
+		return
+	}
+	if modTime := dirModTime(m.currentDir); !modTime.Equal(m.dirModTime) {
+		m.dirModTime = modTime
+		m.reloadDirectory()
+	}
+}
+
+// scheduleDirReload debounces a reload after a change in the browsed directory
+func (m *model) scheduleDirReload() tea.Cmd {
+	m.dirReloadSeq++
+	seq := m.dirReloadSeq
+	return tea.Tick(dirReloadDelay, func(time.Time) tea.Msg {
+		return dirReloadMsg{seq: seq}
+	})
+}
+
+// reloadDirectory re-reads the browsed directory, keeping the cursor on the
+// same entry when it still exists
+func (m *model) reloadDirectory() {
+	var current string
+	if m.fileCursor >= 0 && m.fileCursor < len(m.files) {
+		current = m.files[m.fileCursor].Path
+	}
+	// Sizes can change without the directory's modification time changing
+	m.dirCache.invalidate(m.currentDir)
+	m.loadDirectory()
+	m.moveFileCursorTo(current)
+	m.clampCursors()
+}
+
+// dirModTime returns a directory's modification time, which changes when
+// entries are added, removed or renamed
+func dirModTime(dir string) time.Time {
+	info, err := os.Stat(filepath.Clean(dir))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// standardOptions are lp -o options CUPS handles for every queue, whatever
+// the printer's driver lists
+var standardOptions = map[string]bool{
+	"media": true, "sides": true, "fit-to-page": true, "number-up": true,
+	"orientation-requested": true, "landscape": true, "page-ranges": true,
+	"print-color-mode": true, "print-quality": true, "collate": true,
+	"job-sheets": true, "outputorder": true, "page-set": true, "mirror": true,
+}
+
+// failoverReadyMsg carries the options checked against the printer a failed
+// operation is about to be resubmitted to
+type failoverReadyMsg struct {
+	OpID    string
+	Printer string
+	Extra   []string // Options the printer supports
+	Dropped []string // Options left out because the printer doesn't list them
+}
+
+// printerOptionKeys returns the option names a printer's driver supports,
+// lowercased (`lpoptions -p NAME -l`: "PageSize/Media Size: *Letter A4")
+func printerOptionKeys(printer string) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := runner.Output(ctx, "lpoptions", "-p", printer, "-l")
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		if end := strings.IndexAny(line, "/:"); end > 0 {
+			keys[strings.ToLower(strings.TrimSpace(line[:end]))] = true
+		}
+	}
+	return keys, nil
+}
+
+// failoverCmd checks extra against printer and reports which options to keep.
+// When the printer's options can't be read, only the standard options are kept.
+func failoverCmd(opID, printer string, extra []string) tea.Cmd {
+	return func() tea.Msg {
+		keys, _ := printerOptionKeys(printer)
+		msg := failoverReadyMsg{OpID: opID, Printer: printer}
+		for _, option := range extra {
+			name := strings.ToLower(option)
+			if eq := strings.Index(name, "="); eq != -1 {
+				name = name[:eq]
+			}
+			if standardOptions[name] || keys[name] {
+				msg.Extra = append(msg.Extra, option)
+			} else {
+				msg.Dropped = append(msg.Dropped, option)
+			}
+		}
+		return msg
+	}
+}
+
+// nextReadyPrinter returns the first printer after failed, in discovery order,
+// that accepts jobs. failed is "" for jobs sent to the default printer.
+func (m model) nextReadyPrinter(failed string) (PrinterInfo, bool) {
+	if failed == "" {
+		for _, p := range m.printers {
+			if p.IsDefault {
+				failed = p.Name
+				break
+			}
+		}
+	}
+	start := 0
+	for i, p := range m.printers {
+		if p.Name == failed {
+			start = i + 1
+			break
+		}
+	}
+	for n := 0; n < len(m.printers); n++ {
+		p := m.printers[(start+n)%len(m.printers)]
+		if p.Name == failed || p.Status == "disabled" || p.Status == "stopped" {
+			continue
+		}
+		return p, true
+	}
+	return PrinterInfo{}, false
+}
+
+// retryOnNextPrinter resubmits the failed operation under the cursor to the
+// next ready printer once its options have been checked
+func (m *model) retryOnNextPrinter() tea.Cmd {
+	_, op := m.activeItemAtCursor()
+	if op == nil || op.Status != StatusFailed {
+		m.setStatus("Only failed operations can be sent to another printer")
+		return nil
+	}
+	target, ok := m.nextReadyPrinter(op.Printer)
+	if !ok {
+		m.setError("No other printer is ready")
+		return nil
+	}
+	m.setStatus(fmt.Sprintf("Checking %s for %s…", target.Name, op.FileName))
+	return failoverCmd(op.ID, target.Name, op.Extra)
+}
+
+// applyFailover replaces the failed operation with a submission to msg.Printer
+func (m *model) applyFailover(msg failoverReadyMsg) tea.Cmd {
+	var failed *PrintOperation
+	for i := range m.printOps {
+		if m.printOps[i].ID == msg.OpID {
+			failed = &m.printOps[i]
+			break
+		}
+	}
+	if failed == nil || failed.Status != StatusFailed {
+		// Removed or retried meanwhile
+		return nil
+	}
+	retry := *failed
+	m.removeOperation(retry.ID)
+
+	cmd := m.submitFile(retry.FilePath, PrintOptions{Printer: msg.Printer, Copies: retry.Copies, Duplex: retry.Duplex, Color: retry.Color, Pages: retry.Pages, Extra: msg.Extra})
+	if cmd == nil {
+		return nil
+	}
+	status := fmt.Sprintf("Sent %s to %s", retry.FileName, msg.Printer)
+	if len(msg.Dropped) > 0 {
+		status += " without " + strings.Join(msg.Dropped, ", ")
+	}
+	m.setStatus(status)
+	return cmd
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// validEntryName checks a new file or directory name typed in a prompt
+func validEntryName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("enter a name")
+	case name == "." || name == "..":
+		return fmt.Errorf("%q is not a usable name", name)
+	case strings.ContainsRune(name, filepath.Separator) || strings.ContainsRune(name, '/'):
+		return fmt.Errorf("the name can't contain %q", string(filepath.Separator))
+	}
+	return nil
+}
+
+// openRenamePrompt asks for a new name for the file under the cursor
+func (m *model) openRenamePrompt() tea.Cmd {
+	if m.fileFocus != FocusFileList || m.fileCursor >= len(m.files) {
+		return nil
+	}
+	file := m.files[m.fileCursor]
+	if file.Path == "TOGGLE_ALL" || file.Name == ".." {
+		return nil
+	}
+	m.promptPath = file.Path
+	return m.openPrompt(PromptRename, "Rename: "+file.Name, "new name", file.Name)
+}
+
+// renameFile renames oldPath within its directory, refusing to overwrite,
+// and points staged entries at the new path
+func (m *model) renameFile(oldPath, newName string) error {
+	if err := validEntryName(newName); err != nil {
+		return err
+	}
+	if newName == filepath.Base(oldPath) {
+		return nil
+	}
+	newPath := filepath.Join(filepath.Dir(oldPath), newName)
+	if _, err := os.Lstat(newPath); err == nil {
+		return fmt.Errorf("%s already exists", newName)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+
+	// Staged files are the renamed file itself or, for a directory, inside it
+	moved := func(path string) (string, bool) {
+		if path == oldPath {
+			return newPath, true
+		}
+		if rest, ok := strings.CutPrefix(path, oldPath+string(filepath.Separator)); ok {
+			return filepath.Join(newPath, rest), true
+		}
+		return path, false
+	}
+	for i := range m.stagedFiles {
+		if path, ok := moved(m.stagedFiles[i].Path); ok {
+			m.stagedFiles[i].Path = path
+			m.stagedFiles[i].Name = filepath.Base(path)
+		}
+	}
+	for path := range m.markedFiles {
+		if newMarked, ok := moved(path); ok {
+			delete(m.markedFiles, path)
+			m.markedFiles[newMarked] = true
+		}
+	}
+
+	m.loadDirectory()
+	m.moveFileCursorTo(newPath)
+	m.setStatus(fmt.Sprintf("Renamed %s to %s", filepath.Base(oldPath), newName))
+	return nil
+}
+
+// openMkdirPrompt asks for the name of a directory to create in the browsed directory
+func (m *model) openMkdirPrompt() tea.Cmd {
+	return m.openPrompt(PromptMkdir, "New Folder in "+filepath.Base(m.currentDir), "folder name", "")
+}
+
+// makeDirectory creates name in the browsed directory and puts the cursor on it
+func (m *model) makeDirectory(name string) error {
+	if err := validEntryName(name); err != nil {
+		return err
+	}
+	path := filepath.Join(m.currentDir, name)
+	if err := os.Mkdir(path, 0755); err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("%s already exists", name)
+		}
+		return err
+	}
+
+	m.loadDirectory()
+	m.fileFocus = FocusFileList
+	m.textInput.Blur()
+	m.moveFileCursorTo(path)
+	m.setStatus("Created " + name)
+	return nil
+}
+
+// changeDirectory browses dir if it can be listed. Otherwise it reports why
+// and stays in the current directory, keeping its listing on screen.
+func (m *model) changeDirectory(dir string) bool {
+	if _, err := os.ReadDir(dir); err != nil {
+		// "permission denied" reads better than the full "open /path: ..." form
+		var pathErr *os.PathError
+		if errors.As(err, &pathErr) {
+			err = pathErr.Err
+		}
+		m.setError(fmt.Sprintf("Cannot open %s: %v", filepath.Base(dir), err))
+		return false
+	}
+	m.currentDir = dir
+	m.loadDirectory()
+	return true
+}
+
+// errStageLimit stops the walk in stageTree once the limit is reached
+var errStageLimit = errors.New("stage limit reached")
+
+// stageTree stages every printable file below root, each staged from its own
+// directory. Hidden files and directories are skipped; symlinked directories
+// aren't followed, so links can't loop. At most config.RecursiveStageLimit
+// files are staged.
+func (m *model) stageTree(root string) {
+	staged, unreadable := 0, 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip what can't be read and keep walking the rest
+			unreadable++
+			if entry != nil && entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if path != root && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !entry.Type().IsRegular() || !isPrintableName(entry.Name()) || m.markedFiles[path] {
+			return nil
+		}
+		if staged >= config.RecursiveStageLimit {
+			return errStageLimit
+		}
+		info, err := entry.Info()
+		if err != nil {
+			unreadable++
+			return nil
+		}
+		m.markedFiles[path] = true
+		m.stageFile(entry.Name(), path, filepath.Dir(path), info.Size())
+		staged++
+		return nil
+	})
+
+	name := filepath.Base(root)
+	switch {
+	case err == errStageLimit:
+		m.setError(fmt.Sprintf("Staged the first %d files under %s; recursive_stage_limit stopped the rest", staged, name))
+	case unreadable > 0:
+		m.setError(fmt.Sprintf("Staged %d file(s) under %s; %d couldn't be read", staged, name, unreadable))
+	case staged == 0:
+		m.setStatus("No new printable files under " + name)
+	default:
+		m.setStatus(fmt.Sprintf("Staged %d file(s) under %s", staged, name))
+	}
+}
+
+// moveFileCursorTo puts the file cursor on path if it is listed
+func (m *model) moveFileCursorTo(path string) {
+	for i, file := range m.files {
+		if file.Path == path {
+			m.fileCursor = i
+			return
+		}
+	}
+}
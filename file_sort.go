@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SortMode is the order of the file browser's entries
+type SortMode int
+
+const (
+	SortName     SortMode = iota // Alphabetical (the default)
+	SortSize                     // Smallest first
+	SortModified                 // Oldest first
+	SortExt                      // By extension, then name
+)
+
+// String names the mode for status messages
+func (s SortMode) String() string {
+	switch s {
+	case SortSize:
+		return "size"
+	case SortModified:
+		return "modified"
+	case SortExt:
+		return "extension"
+	}
+	return "name"
+}
+
+// sortFiles orders m.files by the sort mode: the select-all row stays on
+// top and directories come before files whatever the mode or direction
+func (m *model) sortFiles() {
+	sort.SliceStable(m.files, func(i, j int) bool {
+		a, b := m.files[i], m.files[j]
+		// Keep toggle all at the top
+		if a.Path == "TOGGLE_ALL" {
+			return true
+		}
+		if b.Path == "TOGGLE_ALL" {
+			return false
+		}
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		if m.sortDescending {
+			a, b = b, a
+		}
+		switch m.sortMode {
+		case SortSize:
+			if a.Size != b.Size && !a.IsDir {
+				return a.Size < b.Size
+			}
+		case SortModified:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.Before(b.ModTime)
+			}
+		case SortExt:
+			extA, extB := strings.ToLower(filepath.Ext(a.Name)), strings.ToLower(filepath.Ext(b.Name))
+			if extA != extB {
+				return extA < extB
+			}
+		}
+		return a.Name < b.Name
+	})
+}
+
+// cycleSortMode steps the browser through name → size → modified →
+// extension, re-sorting the listing in place
+func (m *model) cycleSortMode() {
+	m.sortMode = (m.sortMode + 1) % 4
+	m.resortFiles()
+}
+
+// toggleSortOrder flips between ascending and descending
+func (m *model) toggleSortOrder() {
+	m.sortDescending = !m.sortDescending
+	m.resortFiles()
+}
+
+// resortFiles applies a new sort without re-reading the directory, keeping
+// the cursor on the same entry
+func (m *model) resortFiles() {
+	var current string
+	if m.fileCursor >= 0 && m.fileCursor < len(m.files) {
+		current = m.files[m.fileCursor].Path
+	}
+	m.sortFiles()
+	m.moveFileCursorTo(current)
+
+	order := "ascending"
+	if m.sortDescending {
+		order = "descending"
+	}
+	m.setStatus(fmt.Sprintf("Sorted by %s, %s", m.sortMode, order))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// writeSettleTime is how long a file must go unmodified before it's taken
+// to be complete; anything newer may still be downloading or being saved
+const writeSettleTime = 2 * time.Second
+
+// partialSuffixes mark files browsers and download tools are still writing
+var partialSuffixes = []string{".part", ".partial", ".crdownload", ".download", ".opdownload"}
+
+// hasPartialSuffix reports whether name is an unfinished download
+func hasPartialSuffix(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, suffix := range partialSuffixes {
+		if ext == suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// looksBeingWritten is a best-effort guess that a file is still being
+// written: an unfinished-download name, a size different from the last
+// listing (prevSize < 0 when unknown), or a modification just now
+func looksBeingWritten(name string, size, prevSize int64, modTime time.Time) bool {
+	if hasPartialSuffix(name) {
+		return true
+	}
+	if prevSize >= 0 && size != prevSize {
+		return true
+	}
+	return time.Since(modTime) < writeSettleTime
+}
+
+// isBeingWritten checks a file that may not be in the current listing
+func (m model) isBeingWritten(path string) bool {
+	for _, file := range m.files {
+		if file.Path == path {
+			return file.Writing
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return looksBeingWritten(info.Name(), info.Size(), -1, info.ModTime())
+}
+
+// recheckWritingFiles reloads the listing while files in it look like they
+// are being written, so their warning clears once they settle. Unfinished
+// downloads only change by being renamed, which reloads the listing anyway.
+func (m *model) recheckWritingFiles() {
+	for _, file := range m.files {
+		if file.Writing && !hasPartialSuffix(file.Name) {
+			m.reloadDirectory()
+			return
+		}
+	}
+}
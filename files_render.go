@@ -2,11 +2,15 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+// emptySuffix flags 0-byte files in the browser and the staged list
+const emptySuffix = " (empty)"
+
 func (m *model) renderFilesContent(width, height int) string {
 	if height <= 0 {
 		return ""
@@ -16,16 +20,26 @@ func (m *model) renderFilesContent(width, height int) string {
 	
 	// Header (not scrollable)
 	header := "📁 File Browser"
+	if m.showMarkedOnly {
+		header += markedStyle.Render(" (marked only)")
+	}
+	if printable, total := m.fileCounts(); total > 0 {
+		// Only when it fits beside the title
+		counts := dimStyle.Render(fmt.Sprintf("  %d printable / %d items", printable, total))
+		if lipgloss.Width(header+counts) <= width {
+			header += counts
+		}
+	}
 	result.WriteString(header)
 	result.WriteString("\n")
 
 	// Input field with visual box
-	inputLine := fmt.Sprintf("┌%s┐", strings.Repeat("─", width-4))
+	inputLine := fmt.Sprintf("┌%s┐", strings.Repeat("─", max(0, width-4)))
 	result.WriteString(dimStyle.Render(inputLine))
 	result.WriteString("\n")
 	result.WriteString(dimStyle.Render("│ ") + m.textInput.View())
 	result.WriteString("\n")
-	inputBottom := fmt.Sprintf("└%s┘", strings.Repeat("─", width-4))
+	inputBottom := fmt.Sprintf("└%s┘", strings.Repeat("─", max(0, width-4)))
 	result.WriteString(dimStyle.Render(inputBottom))
 	result.WriteString("\n")
 	
@@ -38,18 +52,33 @@ func (m *model) renderFilesContent(width, height int) string {
 	
 	// Build file list content for scrollable area
 	var fileListContent strings.Builder
+	var fileRows []int // Item index of each content line, for mouse clicks
+	expandedLines := 0 // Continuation lines of the expanded cursor row
 	
 	if len(m.files) == 0 {
-		fileListContent.WriteString(dimStyle.Render("  No files"))
+		if m.showMarkedOnly {
+			fileListContent.WriteString(dimStyle.Render("  No marked files here"))
+		} else {
+			fileListContent.WriteString(dimStyle.Render("  No files"))
+		}
 	} else {
 		for i, file := range m.files {
 			isCursor := i == m.fileCursor && m.activePane == PaneFiles && m.fileFocus == FocusFileList
 			selectionSymbol := m.getSelectionSymbol(file)
 
 			displayName := file.Name
+			if m.hideExtensions && !file.IsDir && file.Path != "TOGGLE_ALL" {
+				displayName = stripExtension(displayName)
+			}
 			maxNameLen := width - 10
-			if maxNameLen > 0 && len(displayName) > maxNameLen {
-				displayName = displayName[:maxNameLen-3] + "..."
+			isEmpty := !file.IsDir && file.Size == 0
+			if isEmpty && !file.Writing {
+				// Keep room for the flag added after the name
+				maxNameLen -= len(emptySuffix)
+			}
+			displayName, more := m.fitRow(file.Path, displayName, maxNameLen)
+			if isCursor {
+				expandedLines = len(more)
 			}
 
 			// Special handling for toggle all item
@@ -67,6 +96,7 @@ func (m *model) renderFilesContent(width, height int) string {
 				}
 				content := fmt.Sprintf("%s%s", selectAllSymbol, displayName)
 				fileListContent.WriteString(renderSelectable(isCursor, 2, content, selectedFileStyle, selectedStyle))
+				fileRows = append(fileRows, i)
 				if i < len(m.files)-1 {
 					fileListContent.WriteString("\n")
 				}
@@ -83,6 +113,16 @@ func (m *model) renderFilesContent(width, height int) string {
 				typeIndicator = "   "
 			}
 
+			if file.IsDir {
+				if count, ok := m.dirCache.printableCount(file.Path, m.showHidden); ok && count > 0 {
+					displayName += fmt.Sprintf(" (%d printable)", count)
+				}
+			} else if file.Writing {
+				displayName += " ⏳ writing"
+			} else if isEmpty {
+				displayName += emptySuffix
+			}
+
 			content := fmt.Sprintf("%s%s%s", selectionSymbol, typeIndicator, displayName)
 			isMarked := m.markedFiles[file.Path]
 			isMatched := m.matchedFiles[file.Path]
@@ -98,6 +138,12 @@ func (m *model) renderFilesContent(width, height int) string {
 			} else if file.IsDir {
 				selStyle = selectedFileStyle
 				normStyle = dirStyle
+			} else if file.Writing {
+				selStyle = selectedFileStyle
+				normStyle = errorStyle
+			} else if isEmpty && file.IsPrintable {
+				selStyle = selectedFileStyle
+				normStyle = emptyFileStyle
 			} else if file.IsPrintable {
 				selStyle = selectedFileStyle
 				normStyle = printableStyle
@@ -107,6 +153,10 @@ func (m *model) renderFilesContent(width, height int) string {
 			}
 
 			fileListContent.WriteString(renderSelectable(isCursor, 2, content, selStyle, normStyle))
+			fileListContent.WriteString(continuationLines("", 2+lipgloss.Width(selectionSymbol+typeIndicator), more, normStyle))
+			for range len(more) + 1 {
+				fileRows = append(fileRows, i)
+			}
 			if i < len(m.files)-1 {
 				fileListContent.WriteString("\n")
 			}
@@ -114,16 +164,43 @@ func (m *model) renderFilesContent(width, height int) string {
 	}
 	
 	// Create scrollable area for just the file list
-	filesScroll := NewScrollableArea(width, scrollableHeight)
+	filesScroll := m.newScrollArea(width, scrollableHeight)
 	filesScroll.SetContent(fileListContent.String())
 	
-	// Ensure cursor is visible
-	if len(m.files) > 0 && m.fileFocus == FocusFileList {
+	// Ensure cursor is visible (also while typing, so it survives resizes)
+	if len(m.files) > 0 {
+		filesScroll.ScrollToLine(m.fileCursor + expandedLines)
 		filesScroll.ScrollToLine(m.fileCursor)
 	}
 	
-	// Add the scrollable file list to the result
+	// Add the scrollable file list to the result, below the header and input box
+	m.mouse.add(TargetFiles, 4, width, scrollableHeight, filesScroll, fileRows)
 	result.WriteString(filesScroll.Render())
 	
 	return result.String()
-}
\ No newline at end of file
+}
+
+// stripExtension removes the extension from a file name for display,
+// leaving dotfiles like ".bashrc" untouched
+func stripExtension(name string) string {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	if base == "" {
+		return name
+	}
+	return base
+}
+
+// fileCounts counts the printable files and all entries currently listed,
+// leaving out the select-all row
+func (m model) fileCounts() (printable, total int) {
+	for _, file := range m.files {
+		if file.Path == "TOGGLE_ALL" {
+			continue
+		}
+		total++
+		if file.IsPrintable {
+			printable++
+		}
+	}
+	return printable, total
+}
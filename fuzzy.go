@@ -0,0 +1,86 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// patternReloadDelay waits for a pause in typing before re-reading and
+// re-matching the directory, which is slow with thousands of files
+const patternReloadDelay = 150 * time.Millisecond
+
+// patternReloadMsg applies the typed pattern unless more typing superseded it
+type patternReloadMsg struct {
+	seq int
+}
+
+// schedulePatternReload debounces re-matching the directory after the
+// pattern changed; only the last scheduled reload runs
+func (m *model) schedulePatternReload() tea.Cmd {
+	m.patternStale = true
+	m.patternReloadSeq++
+	seq := m.patternReloadSeq
+	return tea.Tick(patternReloadDelay, func(time.Time) tea.Msg {
+		return patternReloadMsg{seq: seq}
+	})
+}
+
+// applyPendingPattern reloads the directory when the pattern changed since
+// the last load and moves the cursor to the best match, so actions on the
+// matches never see a stale set
+func (m *model) applyPendingPattern() {
+	if !m.patternStale {
+		return
+	}
+	m.loadDirectory()
+	m.moveFileCursorTo(m.bestMatch)
+}
+
+// isGlobPattern reports whether the pattern uses glob syntax; anything else
+// is matched fuzzily against file names
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// matchPattern reports whether name matches the browser's pattern and how
+// well. Glob patterns match or don't; plain text matches as a substring or
+// as a subsequence of the name, ignoring case, with higher scores for
+// substrings, matches at the start and tighter subsequences.
+func matchPattern(pattern, name string) (score int, ok bool) {
+	if isGlobPattern(pattern) {
+		matched, _ := filepath.Match(pattern, name)
+		return 0, matched
+	}
+	return fuzzyScore(strings.ToLower(pattern), strings.ToLower(name))
+}
+
+// fuzzyScore scores a lowercase query against a lowercase name
+func fuzzyScore(query, name string) (int, bool) {
+	if query == "" {
+		return 0, false
+	}
+	if i := strings.Index(name, query); i >= 0 {
+		score := 1000 - i
+		if i == 0 {
+			score += 500
+		}
+		return score, true
+	}
+
+	// Subsequence: every query rune in order, penalising the gaps between them
+	score := 500
+	pos := 0
+	for _, r := range query {
+		i := strings.IndexRune(name[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		score -= i
+		pos += i + utf8.RuneLen(r)
+	}
+	return score, true
+}
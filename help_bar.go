@@ -15,6 +15,7 @@ type HelpBar struct {
 	width        int
 	items        []HelpItem
 	showFullHelp bool
+	wrap         bool // Use a second line instead of truncating
 	context      HelpContext
 }
 
@@ -33,18 +34,60 @@ var (
 		{Key: "q", Action: "quit", Global: true},
 	}
 
+	// Less frequent global shortcuts, only listed in the full help window
+	moreGlobalShortcuts = []HelpItem{
+		{Key: "ctrl+g", Action: "go to staged", Global: true},
+		{Key: "ctrl+n/o", Action: "focus next/prev: active→staged→input→list", Global: true},
+		{Key: "ctrl+r", Action: "restage last batch", Global: true},
+		{Key: "E", Action: "export report", Global: true},
+		{Key: "L", Action: "cycle layout", Global: true},
+		{Key: "B", Action: "toggle scrollbar", Global: true},
+		{Key: "W", Action: "wrap help bar", Global: true},
+		{Key: "S", Action: "shell here", Global: true},
+		{Key: "D", Action: "safe mode", Global: true},
+	}
+
 	queueActiveShortcuts = []HelpItem{
 		{Key: "↑↓", Action: "navigate"},
 		{Key: "x", Action: "cancel job"},
 		{Key: "o", Action: "open file"},
+		{Key: "V", Action: "view in pager"},
+		{Key: "i", Action: "details"},
+		{Key: "w", Action: "expand row"},
+		{Key: "F", Action: "failed only"},
+		{Key: "C", Action: "fold completed"},
+		{Key: "[ ]", Action: "prev/next failed"},
+		{Key: "R", Action: "retry"},
+		{Key: "N", Action: "retry on next printer"},
+		{Key: "g", Action: "choose printer"},
+		{Key: "I", Action: "printer status"},
+		{Key: "Q", Action: "all printers"},
+		{Key: "H", Action: "history"},
+		{Key: "y", Action: "copy lp command"},
+		{Key: "t", Action: "pin to top"},
+		{Key: "T", Action: "test page"},
 		{Key: "tab", Action: "switch section"},
 	}
 
 	queueStagedShortcuts = []HelpItem{
 		{Key: "↑↓", Action: "navigate"},
 		{Key: "←→", Action: "copies"},
+		{Key: "0-9", Action: "set copies"},
 		{Key: "x", Action: "remove"},
 		{Key: "o", Action: "open file"},
+		{Key: "c", Action: "contact sheet"},
+		{Key: "s", Action: "print smallest first"},
+		{Key: "g", Action: "choose printer"},
+		{Key: "e", Action: "cups options"},
+		{Key: "b", Action: "print N sets"},
+		{Key: "d", Action: "separate jobs"},
+		{Key: "v", Action: "duplex"},
+		{Key: "G", Action: "gray/color"},
+		{Key: "#", Action: "page range"},
+		{Key: "+", Action: "batch to 2nd printer"},
+		{Key: "n", Action: "note"},
+		{Key: "y", Action: "copy lp command"},
+		{Key: "w", Action: "expand row"},
 	}
 
 	filesInputShortcuts = []HelpItem{
@@ -57,8 +100,20 @@ var (
 		{Key: "↑↓", Action: "navigate"},
 		{Key: "←→", Action: "dirs"},
 		{Key: "space", Action: "mark"},
+		{Key: "s/d", Action: "keep/skip"},
 		{Key: "↑", Action: "to input"},
 		{Key: "pgup/pgdn", Action: "page"},
+		{Key: "e", Action: "toggle extensions"},
+		{Key: "m", Action: "marked only"},
+		{Key: "o/O", Action: "sort/reverse"},
+		{Key: ".", Action: "hidden files"},
+		{Key: "u", Action: "unstage non-matching"},
+		{Key: "i", Action: "invert selection"},
+		{Key: "w", Action: "expand row"},
+		{Key: "r", Action: "rename"},
+		{Key: "n", Action: "new folder"},
+		{Key: "R", Action: "stage tree"},
+		{Key: "ctrl+p", Action: "print dir now"},
 	}
 
 	printShortcuts = []HelpItem{
@@ -71,6 +126,7 @@ var (
 
 	splitViewShortcuts = []HelpItem{
 		{Key: "tab", Action: "switch pane"},
+		{Key: "z", Action: "zoom pane"},
 	}
 
 	// Styles for help items
@@ -231,6 +287,59 @@ func (h *HelpBar) calculateFit() (string, bool) {
 	return truncated, true
 }
 
+// SetWrap chooses between truncating the help bar to one line and wrapping
+// it onto a second line
+func (h *HelpBar) SetWrap(wrap bool) {
+	h.wrap = wrap
+}
+
+// Wrapping reports whether the help bar may use a second line
+func (h *HelpBar) Wrapping() bool {
+	return h.wrap
+}
+
+// Lines returns how many lines the help bar renders for its current items
+func (h *HelpBar) Lines() int {
+	if !h.wrap {
+		return 1
+	}
+	if _, truncated := h.calculateFit(); !truncated {
+		return 1
+	}
+	return 2
+}
+
+// calculateWrapped fills a first line with as many complete items as fit and
+// puts the rest on a second line, truncated like the single-line bar
+func (h *HelpBar) calculateWrapped() (string, string, bool) {
+	separator := helpSeparatorStyle.Render(" • ")
+
+	first := ""
+	currentWidth := 0
+	rest := 0
+	for i, item := range h.items {
+		itemText := renderHelpItem(item)
+		itemWidth := lipgloss.Width(itemText)
+		if i > 0 {
+			itemWidth += lipgloss.Width(separator)
+		}
+		if currentWidth+itemWidth > h.width {
+			break
+		}
+		if i > 0 {
+			first += separator
+		}
+		first += itemText
+		currentWidth += itemWidth
+		rest = i + 1
+	}
+
+	// The second line is the single-line bar over the remaining items
+	second := &HelpBar{width: h.width, items: h.items[rest:]}
+	secondText, truncated := second.calculateFit()
+	return first, secondText, truncated
+}
+
 // Check if full help is showing
 func (h *HelpBar) IsShowingFullHelp() bool {
 	return h.showFullHelp
@@ -244,15 +353,18 @@ func (h *HelpBar) Render() string {
 	
 	helpText, truncated := h.calculateFit()
 
+	if truncated && h.wrap {
+		first, second, stillTruncated := h.calculateWrapped()
+		if stillTruncated {
+			second += h.indicatorPadding(second)
+		}
+		return helpStyle.Copy().Width(h.width).Render(first) + "\n" +
+			helpStyle.Copy().Width(h.width).Render(second)
+	}
+
 	if truncated {
 		// Add the "?" indicator on the right
-		indicator := helpIndicatorStyle.Render(" [?]")
-		remainingWidth := h.width - lipgloss.Width(helpText) - lipgloss.Width(indicator)
-		
-		if remainingWidth > 0 {
-			helpText += strings.Repeat(" ", remainingWidth)
-		}
-		helpText += indicator
+		helpText += h.indicatorPadding(helpText)
 	}
 
 	return helpStyle.Copy().
@@ -260,6 +372,16 @@ func (h *HelpBar) Render() string {
 		Render(helpText)
 }
 
+// indicatorPadding right-aligns the "[?]" indicator after text
+func (h *HelpBar) indicatorPadding(text string) string {
+	indicator := helpIndicatorStyle.Render(" [?]")
+	remainingWidth := h.width - lipgloss.Width(text) - lipgloss.Width(indicator)
+	if remainingWidth > 0 {
+		return strings.Repeat(" ", remainingWidth) + indicator
+	}
+	return indicator
+}
+
 // Render the full help window (floating overlay)
 func (h *HelpBar) RenderFullHelp() string {
 	var content strings.Builder
@@ -275,6 +397,11 @@ func (h *HelpBar) RenderFullHelp() string {
 		content.WriteString(renderHelpItem(item))
 		content.WriteString("\n")
 	}
+	for _, item := range moreGlobalShortcuts {
+		content.WriteString("  ")
+		content.WriteString(renderHelpItem(item))
+		content.WriteString("\n")
+	}
 
 	// Queue pane shortcuts - Active section
 	content.WriteString("\n")
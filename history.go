@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyVisibleRows is how many history entries the overlay shows at once
+const historyVisibleRows = 15
+
+// HistoryEntry records one print sent to the printer
+type HistoryEntry struct {
+	FilePath string    `json:"file_path"`
+	FileName string    `json:"file_name"`
+	Printer  string    `json:"printer,omitempty"` // "" for the system default
+	Copies   int       `json:"copies"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+// loadHistory reads history.json; a missing or corrupt file starts empty
+func (t *JobTracker) loadHistory() {
+	data, err := os.ReadFile(t.historyPath)
+	if err != nil {
+		return
+	}
+	if json.Unmarshal(data, &t.history) != nil {
+		t.history = nil
+	}
+}
+
+// AddHistory appends a sent print, keeps at most config.HistoryLimit
+// entries and saves. If saving fails the history stays in memory only and
+// the error is returned once.
+func (t *JobTracker) AddHistory(entry HistoryEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.history = append(t.history, entry)
+	if limit := config.HistoryLimit; limit > 0 && len(t.history) > limit {
+		t.history = append([]HistoryEntry(nil), t.history[len(t.history)-limit:]...)
+	}
+	if t.historyPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(t.history, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(t.historyPath, data); err != nil {
+		t.historyPath = ""
+		return fmt.Errorf("cannot save print history, continuing in memory only: %v", err)
+	}
+	return nil
+}
+
+// History returns the print history, newest first
+func (t *JobTracker) History() []HistoryEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entries := make([]HistoryEntry, len(t.history))
+	for i, entry := range t.history {
+		entries[len(entries)-1-i] = entry
+	}
+	return entries
+}
+
+// recordHistory adds a sent operation to the print history
+func (m *model) recordHistory(op PrintOperation) {
+	err := m.tracker.AddHistory(HistoryEntry{
+		FilePath: op.FilePath,
+		FileName: op.FileName,
+		Printer:  op.Printer,
+		Copies:   op.Copies,
+		SentAt:   time.Now(),
+	})
+	if err != nil {
+		m.setNotice(err.Error(), 10*time.Second)
+	}
+}
+
+// openHistory shows the print history, newest first
+func (m *model) openHistory() {
+	m.historyEntries = m.tracker.History()
+	m.historyCursor = 0
+	m.overlay = OverlayHistory
+}
+
+// updateHistory handles keys while the history overlay is open
+func (m model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "H":
+		m.overlay = OverlayNone
+
+	case "up", "k":
+		if m.historyCursor > 0 {
+			m.historyCursor--
+		}
+
+	case "down", "j":
+		if m.historyCursor < len(m.historyEntries)-1 {
+			m.historyCursor++
+		}
+
+	case "enter", "s":
+		if m.historyCursor < len(m.historyEntries) {
+			m.restageHistory(m.historyEntries[m.historyCursor])
+		}
+
+	case "R":
+		if m.historyCursor < len(m.historyEntries) {
+			cmd := m.openHistoryReprintPicker(m.historyEntries[m.historyCursor])
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+// openHistoryReprintPicker asks which printer to print a history entry on
+// again, with the copies it was printed with
+func (m *model) openHistoryReprintPicker(entry HistoryEntry) tea.Cmd {
+	info, err := os.Stat(entry.FilePath)
+	if err != nil || info.IsDir() {
+		m.setError(entry.FileName + " no longer exists")
+		return nil
+	}
+	m.reprintPath = entry.FilePath
+	m.reprintName = entry.FileName
+	m.reprintCopies = entry.Copies
+	return m.openPrinterPicker(PickReprint)
+}
+
+// restageHistory stages a file from the history again with the printer and
+// copies it was printed with
+func (m *model) restageHistory(entry HistoryEntry) {
+	info, err := os.Stat(entry.FilePath)
+	if err != nil || info.IsDir() {
+		m.setError(entry.FileName + " no longer exists")
+		return
+	}
+	staged := len(m.stagedFiles)
+	m.stageFile(info.Name(), entry.FilePath, filepath.Dir(entry.FilePath), info.Size())
+	if len(m.stagedFiles) == staged {
+		// Refused, e.g. the file is being written; stageFile said why
+		return
+	}
+	m.markedFiles[entry.FilePath] = true
+	file := &m.stagedFiles[len(m.stagedFiles)-1]
+	if entry.Printer != "" {
+		file.Printer = entry.Printer
+	}
+	if entry.Copies > 0 {
+		file.Copies = entry.Copies
+	}
+	m.setStatus("Staged " + entry.FileName + " again")
+}
+
+// renderHistory renders the print history overlay around the cursor
+func (m model) renderHistory() string {
+	var content strings.Builder
+	content.WriteString(helpWindowTitleStyle.Render("Print History"))
+	content.WriteString("\n\n")
+
+	if len(m.historyEntries) == 0 {
+		content.WriteString(dimStyle.Render("Nothing printed yet"))
+		content.WriteString("\n")
+	}
+
+	start := 0
+	if m.historyCursor >= historyVisibleRows {
+		start = m.historyCursor - historyVisibleRows + 1
+	}
+	end := min(len(m.historyEntries), start+historyVisibleRows)
+	for i := start; i < end; i++ {
+		entry := m.historyEntries[i]
+		label := fmt.Sprintf("%s  %s%s", entry.SentAt.Format("Jan 2 15:04"),
+			truncate(sanitizeForDisplay(entry.FileName), 40), copiesSuffix(entry.Copies))
+		content.WriteString(renderSelectable(i == m.historyCursor, 2, label, selectedFileStyle, normalStyle))
+		if entry.Printer != "" {
+			content.WriteString(dimStyle.Render(" → " + sanitizeForDisplay(entry.Printer)))
+		}
+		content.WriteString("\n")
+	}
+	if len(m.historyEntries) > historyVisibleRows {
+		content.WriteString(dimStyle.Render(fmt.Sprintf("  %d/%d", m.historyCursor+1, len(m.historyEntries))))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(helpActionStyle.Render("enter/s: stage again • R: reprint to… • esc: close"))
+	return helpWindowStyle.Render(content.String())
+}
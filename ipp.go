@@ -0,0 +1,555 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Minimal IPP/1.1 client (RFC 8010) for talking to the CUPS scheduler directly,
+// covering just the operations and value types this app needs
+
+// IPP operation IDs
+const (
+	ippOpPrintJob        = 0x0002
+	ippOpCancelJob       = 0x0008
+	ippOpGetJobs         = 0x000A
+	ippOpCUPSGetDefault  = 0x4001
+	ippOpCUPSGetPrinters = 0x4002
+)
+
+// IPP delimiter and value tags
+const (
+	ippTagOperation = 0x01
+	ippTagJob       = 0x02
+	ippTagEnd       = 0x03
+	ippTagPrinter   = 0x04
+	ippTagInteger   = 0x21
+	ippTagBoolean   = 0x22
+	ippTagEnum      = 0x23
+	ippTagRange     = 0x33 // rangeOfInteger
+	ippTagName      = 0x42
+	ippTagKeyword   = 0x44
+	ippTagURI       = 0x45
+	ippTagCharset   = 0x47
+	ippTagLanguage  = 0x48
+	ippTagMimeType  = 0x49
+)
+
+// IPP job-state and printer-state enum values
+const (
+	ippJobProcessing     = 5
+	ippPrinterIdle       = 3
+	ippPrinterProcessing = 4
+)
+
+// ippAttr is one attribute with one or more encoded values
+type ippAttr struct {
+	tag    byte
+	name   string
+	values [][]byte
+}
+
+func ippString(tag byte, name string, values ...string) ippAttr {
+	attr := ippAttr{tag: tag, name: name}
+	for _, v := range values {
+		attr.values = append(attr.values, []byte(v))
+	}
+	return attr
+}
+
+func ippInteger(tag byte, name string, value int) ippAttr {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, uint32(int32(value)))
+	return ippAttr{tag: tag, name: name, values: [][]byte{data}}
+}
+
+// ippOption encodes a raw key=value option as a job attribute, guessing its
+// type the way lp -o does: integers, booleans, otherwise a keyword.
+// page-ranges is sent as rangeOfInteger values, which is the only form CUPS
+// accepts; a malformed range is an error rather than a job printing every page.
+func ippOption(option string) (ippAttr, error) {
+	key, value, _ := strings.Cut(option, "=")
+	if key == "page-ranges" {
+		return ippPageRanges(value)
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return ippInteger(ippTagInteger, key, n), nil
+	}
+	if b, err := strconv.ParseBool(value); err == nil && (value == "true" || value == "false") {
+		data := []byte{0}
+		if b {
+			data[0] = 1
+		}
+		return ippAttr{tag: ippTagBoolean, name: key, values: [][]byte{data}}, nil
+	}
+	return ippString(ippTagKeyword, key, value), nil
+}
+
+// ippPageRanges encodes "2-5,8" as the 1setOf rangeOfInteger 2-5, 8-8
+func ippPageRanges(value string) (ippAttr, error) {
+	attr := ippAttr{tag: ippTagRange, name: "page-ranges"}
+	for _, part := range strings.Split(value, ",") {
+		lowText, highText, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		if !isRange {
+			highText = lowText
+		}
+		low, errLow := strconv.Atoi(lowText)
+		high, errHigh := strconv.Atoi(highText)
+		if errLow != nil || errHigh != nil || low < 1 || high < low {
+			return ippAttr{}, fmt.Errorf("invalid page range %q", value)
+		}
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint32(data[:4], uint32(low))
+		binary.BigEndian.PutUint32(data[4:], uint32(high))
+		attr.values = append(attr.values, data)
+	}
+	return attr, nil
+}
+
+// ippRequest is an operation with its operation and job attribute groups
+type ippRequest struct {
+	op        uint16
+	operation []ippAttr
+	job       []ippAttr
+}
+
+// newIPPRequest starts a request with the attributes every operation requires
+func newIPPRequest(op uint16, targetName, targetURI string) *ippRequest {
+	return &ippRequest{
+		op: op,
+		operation: []ippAttr{
+			ippString(ippTagCharset, "attributes-charset", "utf-8"),
+			ippString(ippTagLanguage, "attributes-natural-language", "en"),
+			ippString(ippTagURI, targetName, targetURI),
+			ippString(ippTagName, "requesting-user-name", ippUserName()),
+		},
+	}
+}
+
+// encode serializes the request header and attribute groups
+func (r *ippRequest) encode() []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{1, 1}) // IPP/1.1
+	binary.Write(&buf, binary.BigEndian, r.op)
+	binary.Write(&buf, binary.BigEndian, uint32(1)) // request-id
+
+	writeGroup := func(tag byte, attrs []ippAttr) {
+		if len(attrs) == 0 {
+			return
+		}
+		buf.WriteByte(tag)
+		for _, attr := range attrs {
+			for i, value := range attr.values {
+				name := attr.name
+				if i > 0 {
+					name = "" // Additional values of a 1setOf have no name
+				}
+				buf.WriteByte(attr.tag)
+				binary.Write(&buf, binary.BigEndian, uint16(len(name)))
+				buf.WriteString(name)
+				binary.Write(&buf, binary.BigEndian, uint16(len(value)))
+				buf.Write(value)
+			}
+		}
+	}
+	writeGroup(ippTagOperation, r.operation)
+	writeGroup(ippTagJob, r.job)
+	buf.WriteByte(ippTagEnd)
+	return buf.Bytes()
+}
+
+// ippValue is a single decoded attribute value
+type ippValue struct {
+	tag  byte
+	data []byte
+}
+
+func (v ippValue) String() string {
+	return string(v.data)
+}
+
+func (v ippValue) Int() int {
+	if len(v.data) != 4 {
+		return 0
+	}
+	return int(int32(binary.BigEndian.Uint32(v.data)))
+}
+
+// ippGroup is one attribute group of a response, e.g. a single job or printer
+type ippGroup struct {
+	tag   byte
+	attrs map[string][]ippValue
+}
+
+func (g ippGroup) str(name string) string {
+	if values := g.attrs[name]; len(values) > 0 {
+		return values[0].String()
+	}
+	return ""
+}
+
+func (g ippGroup) integer(name string) (int, bool) {
+	if values := g.attrs[name]; len(values) > 0 {
+		return values[0].Int(), true
+	}
+	return 0, false
+}
+
+// ippResponse is a decoded response: status code and attribute groups
+type ippResponse struct {
+	status uint16
+	groups []ippGroup
+}
+
+// groupsOf returns the response groups with the given delimiter tag
+func (r *ippResponse) groupsOf(tag byte) []ippGroup {
+	var groups []ippGroup
+	for _, g := range r.groups {
+		if g.tag == tag {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// parseIPPResponse decodes the header and attribute groups of an IPP response
+func parseIPPResponse(data []byte) (*ippResponse, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("short IPP response")
+	}
+	resp := &ippResponse{status: binary.BigEndian.Uint16(data[2:4])}
+
+	pos := 8
+	next := func(n int) ([]byte, error) {
+		if pos+n > len(data) {
+			return nil, fmt.Errorf("truncated IPP response")
+		}
+		b := data[pos : pos+n]
+		pos += n
+		return b, nil
+	}
+
+	lastName := ""
+	for pos < len(data) {
+		tag := data[pos]
+		pos++
+
+		if tag == ippTagEnd {
+			break
+		}
+		if tag < 0x10 {
+			// Delimiter tag starts a new group
+			resp.groups = append(resp.groups, ippGroup{tag: tag, attrs: make(map[string][]ippValue)})
+			continue
+		}
+		if len(resp.groups) == 0 {
+			return nil, fmt.Errorf("IPP value outside an attribute group")
+		}
+
+		lenBytes, err := next(2)
+		if err != nil {
+			return nil, err
+		}
+		name, err := next(int(binary.BigEndian.Uint16(lenBytes)))
+		if err != nil {
+			return nil, err
+		}
+		lenBytes, err = next(2)
+		if err != nil {
+			return nil, err
+		}
+		value, err := next(int(binary.BigEndian.Uint16(lenBytes)))
+		if err != nil {
+			return nil, err
+		}
+
+		// An empty name adds another value to the previous attribute
+		if len(name) > 0 {
+			lastName = string(name)
+		}
+		group := resp.groups[len(resp.groups)-1]
+		group.attrs[lastName] = append(group.attrs[lastName], ippValue{tag: tag, data: value})
+	}
+	return resp, nil
+}
+
+// ippUserName returns the user name sent as requesting-user-name
+func ippUserName() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "anonymous"
+}
+
+// ippBackend talks IPP to the CUPS scheduler over HTTP. When the scheduler
+// can't be reached it falls back to the CLI backend.
+type ippBackend struct {
+	server   string // e.g. "http://localhost:631"
+	client   *http.Client
+	fallback PrintBackend
+}
+
+func newIPPBackend(server string, fallback PrintBackend) *ippBackend {
+	if server == "" {
+		server = "http://localhost:631"
+	}
+	return &ippBackend{
+		server:   server,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		fallback: fallback,
+	}
+}
+
+// isUnreachable reports whether err means the scheduler couldn't be contacted
+// at all, so nothing was sent and retrying with the fallback is safe
+func isUnreachable(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// do posts an IPP request (followed by optional document data) to path
+func (b *ippBackend) do(path string, req *ippRequest, document io.Reader) (*ippResponse, error) {
+	body := io.Reader(bytes.NewReader(req.encode()))
+	if document != nil {
+		body = io.MultiReader(body, document)
+	}
+
+	httpResp, err := b.client.Post(b.server+path, "application/ipp", body)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("IPP request failed: %s", httpResp.Status)
+	}
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := parseIPPResponse(data)
+	if err != nil {
+		return nil, err
+	}
+	// Status codes 0x0000-0x00FF are successful
+	if resp.status >= 0x0100 {
+		if ops := resp.groupsOf(ippTagOperation); len(ops) > 0 && ops[0].str("status-message") != "" {
+			return nil, fmt.Errorf("%s", ops[0].str("status-message"))
+		}
+		return nil, fmt.Errorf("IPP error 0x%04x", resp.status)
+	}
+	return resp, nil
+}
+
+// printerURI returns the IPP URI for a CUPS queue name
+func printerURI(name string) string {
+	return "ipp://localhost/printers/" + url.PathEscape(name)
+}
+
+// defaultPrinter returns the user's default destination ($LPDEST, $PRINTER,
+// lpoptions), otherwise asks CUPS for the system default
+func (b *ippBackend) defaultPrinter() (string, error) {
+	if dest := cupsUserDefault(); dest != "" {
+		return dest, nil
+	}
+	resp, err := b.do("/", newIPPRequest(ippOpCUPSGetDefault, "printer-uri", "ipp://localhost/"), nil)
+	if err != nil {
+		return "", err
+	}
+	for _, g := range resp.groupsOf(ippTagPrinter) {
+		if name := g.str("printer-name"); name != "" {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no default printer")
+}
+
+// Submit sends the file with Print-Job; CUPS replies with the job ID directly
+func (b *ippBackend) Submit(filePath string, opts PrintOptions) (string, error) {
+	printer := opts.Printer
+	if printer == "" {
+		name, err := b.defaultPrinter()
+		if isUnreachable(err) {
+			return b.fallback.Submit(filePath, opts)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to print: %v", err)
+		}
+		printer = name
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to print: %v", err)
+	}
+	defer f.Close()
+
+	copies := opts.Copies
+	if copies < 1 {
+		copies = 1
+	}
+	req := newIPPRequest(ippOpPrintJob, "printer-uri", printerURI(queueName(printer)))
+	req.operation = append(req.operation,
+		ippString(ippTagName, "job-name", filepath.Base(filePath)),
+		ippString(ippTagMimeType, "document-format", "application/octet-stream"),
+	)
+	req.job = append(req.job, ippInteger(ippTagInteger, "copies", copies))
+	// lp applies the user's lpoptions itself; over IPP we add them here,
+	// skipping copies since it's already set above
+	explicit := append([]string{"copies"}, opts.cupsOptions()...)
+	for _, option := range append(lpoptionsFor(printer, explicit), explicit[1:]...) {
+		attr, err := ippOption(option)
+		if err != nil {
+			return "", fmt.Errorf("failed to print: %v", err)
+		}
+		req.job = append(req.job, attr)
+	}
+
+	resp, err := b.do("/printers/"+url.PathEscape(queueName(printer)), req, f)
+	if isUnreachable(err) {
+		return b.fallback.Submit(filePath, opts)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to print: %v", err)
+	}
+
+	for _, g := range resp.groupsOf(ippTagJob) {
+		if id, ok := g.integer("job-id"); ok {
+			return strconv.Itoa(id), nil
+		}
+	}
+	return "", nil
+}
+
+// ListJobs returns not-completed jobs on all printers, ranked like lpq
+func (b *ippBackend) ListJobs() ([]PrintJob, error) {
+	req := newIPPRequest(ippOpGetJobs, "printer-uri", "ipp://localhost/")
+	req.operation = append(req.operation,
+		ippString(ippTagKeyword, "which-jobs", "not-completed"),
+		ippString(ippTagKeyword, "requested-attributes",
+			"job-id", "job-name", "document-name-supplied", "job-originating-user-name", "job-k-octets", "job-state"),
+	)
+
+	resp, err := b.do("/", req, nil)
+	if isUnreachable(err) {
+		return b.fallback.ListJobs()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+
+	jobs := []PrintJob{}
+	rank := 0
+	for _, g := range resp.groupsOf(ippTagJob) {
+		id, ok := g.integer("job-id")
+		if !ok {
+			continue
+		}
+		jobID := strconv.Itoa(id)
+
+		fileName := g.str("job-name")
+		if isPlaceholderJobName(fileName) {
+			fileName = g.str("document-name-supplied")
+		}
+		if isPlaceholderJobName(fileName) {
+			fileName = fmt.Sprintf("Job %s", jobID)
+		}
+
+		size := int64(-1)
+		if kOctets, ok := g.integer("job-k-octets"); ok {
+			size = int64(kOctets) * 1024
+		}
+
+		status := "active"
+		if state, _ := g.integer("job-state"); state != ippJobProcessing {
+			rank++
+			status = ordinal(rank)
+		}
+
+		jobs = append(jobs, PrintJob{
+			ID:       jobID,
+			FileName: filepath.Base(fileName),
+			Owner:    g.str("job-originating-user-name"),
+			Size:     size,
+			Status:   status,
+		})
+	}
+	return jobs, nil
+}
+
+// Cancel cancels a job by its ID with Cancel-Job
+func (b *ippBackend) Cancel(jobID string) error {
+	req := newIPPRequest(ippOpCancelJob, "job-uri", "ipp://localhost/jobs/"+jobID)
+	_, err := b.do("/jobs/", req, nil)
+	if isUnreachable(err) {
+		return b.fallback.Cancel(jobID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel job %s: %v", jobID, err)
+	}
+	return nil
+}
+
+// ListPrinters returns every CUPS queue with its state
+func (b *ippBackend) ListPrinters() []PrinterInfo {
+	req := newIPPRequest(ippOpCUPSGetPrinters, "printer-uri", "ipp://localhost/")
+	req.operation = append(req.operation,
+		ippString(ippTagKeyword, "requested-attributes", "printer-name", "printer-state"))
+
+	resp, err := b.do("/", req, nil)
+	if isUnreachable(err) {
+		return b.fallback.ListPrinters()
+	}
+	if err != nil {
+		return []PrinterInfo{}
+	}
+
+	defaultName, _ := b.defaultPrinter()
+	printers := []PrinterInfo{}
+	for _, g := range resp.groupsOf(ippTagPrinter) {
+		name := g.str("printer-name")
+		if name == "" {
+			continue
+		}
+		status := "stopped"
+		switch state, _ := g.integer("printer-state"); state {
+		case ippPrinterIdle:
+			status = "idle"
+		case ippPrinterProcessing:
+			status = "printing"
+		}
+		printers = append(printers, PrinterInfo{Name: name, Status: status, IsDefault: name == defaultName})
+	}
+	return printers
+}
+
+// ordinal formats a queue rank the way lpq does: 1st, 2nd, 3rd, 4th, 11th...
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIPPOptionPageRanges(t *testing.T) {
+	tests := []struct {
+		value string
+		want  [][2]int
+	}{
+		{"5", [][2]int{{5, 5}}},
+		{"2-5", [][2]int{{2, 5}}},
+		{"2-5,8", [][2]int{{2, 5}, {8, 8}}},
+		{"1-3, 7-9", [][2]int{{1, 3}, {7, 9}}},
+	}
+	for _, tt := range tests {
+		attr, err := ippOption("page-ranges=" + tt.value)
+		if err != nil {
+			t.Errorf("ippOption(%q) error = %v", tt.value, err)
+			continue
+		}
+		if attr.tag != ippTagRange || attr.name != "page-ranges" || len(attr.values) != len(tt.want) {
+			t.Errorf("ippOption(%q) = tag %#x, %d values; want rangeOfInteger with %d", tt.value, attr.tag, len(attr.values), len(tt.want))
+			continue
+		}
+		for i, data := range attr.values {
+			low, high := int(binary.BigEndian.Uint32(data[:4])), int(binary.BigEndian.Uint32(data[4:]))
+			if len(data) != 8 || low != tt.want[i][0] || high != tt.want[i][1] {
+				t.Errorf("ippOption(%q) value %d = %d-%d, want %d-%d", tt.value, i, low, high, tt.want[i][0], tt.want[i][1])
+			}
+		}
+	}
+}
+
+func TestIPPOptionRejectsBadPageRanges(t *testing.T) {
+	for _, value := range []string{"", "0", "5-2", "a-b", "2-", "1,,3"} {
+		if _, err := ippOption("page-ranges=" + value); err == nil {
+			t.Errorf("ippOption(page-ranges=%q) accepted a malformed range", value)
+		}
+	}
+}
+
+func TestIPPOptionGuessesTypes(t *testing.T) {
+	tests := []struct {
+		option string
+		tag    byte
+	}{
+		{"number-up=2", ippTagInteger},
+		{"fit-to-page=true", ippTagBoolean},
+		{"media=A4", ippTagKeyword},
+	}
+	for _, tt := range tests {
+		attr, err := ippOption(tt.option)
+		if err != nil || attr.tag != tt.tag {
+			t.Errorf("ippOption(%q) = tag %#x, %v; want tag %#x", tt.option, attr.tag, err, tt.tag)
+		}
+	}
+}
+
+func TestIPPSubmitSendsEachOptionOnce(t *testing.T) {
+	writeLPOptions(t, "Dest Office copies=3 media=A4 sides=one-sided\n")
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		// IPP/1.1 successful-ok with an empty operation group
+		w.Write([]byte{1, 1, 0, 0, 0, 0, 0, 1, ippTagOperation, ippTagEnd})
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(file, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := PrintOptions{Printer: "Office", Copies: 2, Duplex: DuplexLong, Color: ColorGray,
+		Extra: []string{"sides=one-sided", "ColorModel=RGB", "number-up=2"}}
+	if _, err := newIPPBackend(srv.URL, nil).Submit(file, opts); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// The request is framed like a response, so the same parser reads it back
+	req, err := parseIPPResponse(body)
+	if err != nil {
+		t.Fatalf("parsing the sent request: %v", err)
+	}
+	jobs := req.groupsOf(ippTagJob)
+	if len(jobs) != 1 {
+		t.Fatalf("request has %d job groups, want 1", len(jobs))
+	}
+	job := jobs[0]
+	// Every job attribute sent here is single-valued, so more than one value
+	// means it was sent twice
+	for name, values := range job.attrs {
+		if len(values) != 1 {
+			t.Errorf("job attribute %s sent %d times", name, len(values))
+		}
+	}
+	if copies, _ := job.integer("copies"); copies != 2 {
+		t.Errorf("copies = %d, want 2 from the explicit option", copies)
+	}
+	want := map[string]string{"sides": "two-sided-long-edge", "ColorModel": "Gray", "media": "A4", "number-up": ""}
+	for name, value := range want {
+		if _, ok := job.attrs[name]; !ok {
+			t.Errorf("job attribute %s missing", name)
+		} else if value != "" && job.str(name) != value {
+			t.Errorf("%s = %q, want %q", name, job.str(name), value)
+		}
+	}
+}
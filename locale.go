@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// Languages whose locales write decimals with a comma (e.g. "1,5 MB")
+var commaDecimalLanguages = map[string]bool{
+	"bg": true, "ca": true, "cs": true, "da": true, "de": true, "el": true,
+	"es": true, "et": true, "fi": true, "fr": true, "hr": true, "hu": true,
+	"id": true, "it": true, "lt": true, "lv": true, "nb": true, "nl": true,
+	"nn": true, "no": true, "pl": true, "pt": true, "ro": true, "ru": true,
+	"sk": true, "sl": true, "sr": true, "sv": true, "tr": true, "uk": true,
+	"vi": true,
+}
+
+// decimalSeparator is the separator for formatted numbers, from the user's locale
+var decimalSeparator = localeDecimalSeparator()
+
+// localeDecimalSeparator picks "," or "." from the numeric locale environment,
+// following POSIX precedence: LC_ALL, then LC_NUMERIC, then LANG
+func localeDecimalSeparator() string {
+	locale := ""
+	for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			locale = v
+			break
+		}
+	}
+
+	// "de_DE.UTF-8" -> "de"
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "_.@-"); i != -1 {
+		lang = lang[:i]
+	}
+	if commaDecimalLanguages[lang] {
+		return ","
+	}
+	return "."
+}
+
+// localizeDecimal swaps the "." in a formatted number for the locale's separator.
+// The output keeps the same width, so aligned columns stay aligned.
+func localizeDecimal(s string) string {
+	if decimalSeparator == "." {
+		return s
+	}
+	return strings.Replace(s, ".", decimalSeparator, 1)
+}
@@ -7,18 +7,29 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fsnotify/fsnotify"
 )
 
 const version = "0.3.0"
 
 // Printable file extensions (single source of truth)
-var printableExts = []string{".pdf", ".txt", ".doc", ".docx", ".jpg", ".jpeg", ".png", ".gif"}
+// defaultPrintableExts are the extensions offered for printing unless the
+// config replaces them (printable_extensions) or adds to them
+// (extra_printable_extensions); use isPrintableName to check a file
+var defaultPrintableExts = []string{
+	".pdf", ".ps", ".txt", ".md", ".rtf", ".html", ".htm",
+	".doc", ".docx", ".odt",
+	".jpg", ".jpeg", ".png", ".gif",
+}
 
 var (
 	// Base styles
@@ -92,6 +103,21 @@ var (
 			Foreground(theme.Red).
 			Bold(true)
 
+	emptyFileStyle = lipgloss.NewStyle().
+			Foreground(theme.Peach)
+
+	allDoneStyle = lipgloss.NewStyle().
+			Foreground(theme.Green).
+			Bold(true)
+
+	safeModeBannerStyle = lipgloss.NewStyle().
+				Foreground(theme.Base).
+				Background(theme.Peach).
+				Bold(true)
+
+	memoryOnlyBadgeStyle = lipgloss.NewStyle().
+				Foreground(theme.Yellow)
+
 	// Border styles
 	activeBorderStyle = lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
@@ -123,6 +149,29 @@ const (
 	LayoutVertical
 )
 
+// LayoutOverride lets the user force a layout instead of the size-based choice
+type LayoutOverride int
+
+const (
+	LayoutAuto LayoutOverride = iota
+	LayoutForceSingle
+	LayoutForceHorizontal
+	LayoutForceVertical
+)
+
+func (o LayoutOverride) String() string {
+	switch o {
+	case LayoutForceSingle:
+		return "single pane"
+	case LayoutForceHorizontal:
+		return "side by side"
+	case LayoutForceVertical:
+		return "stacked"
+	default:
+		return "auto"
+	}
+}
+
 type ActivePane int
 
 const (
@@ -150,13 +199,16 @@ type FileItem struct {
 	IsDir       bool
 	IsPrintable bool
 	Size        int64
+	ModTime     time.Time
+	Writing     bool // Looks like it's still being written (download in progress)
 }
 
 type PrintJob struct {
 	ID       string
 	FileName string
 	FilePath string // Empty for system jobs, populated for our PrintOperations
-	Size     int64
+	Owner    string
+	Size     int64 // -1 when lpq reported a size we couldn't parse
 	Status   string
 }
 
@@ -166,17 +218,27 @@ type StagedFile struct {
 	StagedFrom    string // Directory this was staged from
 	Size          int64
 	AddedAt       time.Time
-	Copies        int  // Number of copies to print (default 1)
-	PendingRemove bool // Shows "?" when true, next left removes
+	Copies        int        // Number of copies to print (default 1)
+	Duplex        DuplexMode // One- or two-sided output
+	Color         ColorMode  // Color, grayscale or the printer's default
+	Pages         string     // Page range to print, e.g. "2-5"; "" for all pages
+	Printer       string     // Destination printer, "" for the default
+	ExtraOptions  []string   // Raw CUPS options passed as -o key=value
+	Note          string     // Free-text tag for the user's own bookkeeping, not printed
+	PendingRemove bool       // Shows "?" when true, next left removes
 }
 
 type model struct {
-	layoutMode LayoutMode
+	layoutMode     LayoutMode
+	layoutOverride LayoutOverride
+	zoomed         bool // Show only the active pane in a split layout (like tmux zoom)
 	activePane ActivePane
 	fileFocus  FileFocus
 
 	// Queue state
 	jobs         []PrintJob
+	printers     []PrinterInfo // Printers discovered via lpstat, refreshed on demand
+	pinnedJobs   map[string]bool // Job IDs kept at the top of the active list
 	stagedFiles  []StagedFile
 	queueSection QueueSection
 	activeCursor int
@@ -188,19 +250,124 @@ type model struct {
 	height int
 
 	// File browser state
-	textInput       textinput.Model
-	currentDir      string
-	files           []FileItem
-	fileCursor      int
-	markedFiles     map[string]bool // Files checked for staging
-	matchedFiles    map[string]bool // Files matching pattern (visual only)
-	dirCursorMemory map[string]int  // Remember cursor position for each directory
+	textInput         textinput.Model
+	currentDir        string
+	files             []FileItem
+	fileCursor        int
+	markedFiles       map[string]bool      // Files checked for staging
+	matchedFiles      map[string]bool      // Files matching pattern (visual only)
+	bestMatch         string               // Best-scoring match, followed while typing
+	patternReloadSeq  int                  // Debounces reloads while typing a pattern
+	patternStale      bool                 // matchedFiles predates the pattern being typed
+	dirCursorMemory   map[string]int       // Remember cursor position for each directory
+	queueCursorMemory map[QueueSection]int // Remember cursor position for each queue section
 
 	// Print operations state
 	printOps     []PrintOperation
 
+	// Digits typed on a staged file to set its copies directly
+	copiesDigits   string
+	copiesDigitsAt time.Time
+
 	// Help bar component
 	helpBar *HelpBar
+	mouse   *mouseLayout // Where lists were last drawn, for clicks and the wheel
+
+	// Activity indicator: commands dispatched whose result hasn't come back yet
+	inFlight int
+	spinner  spinner.Model
+
+	// Transient status line shown in place of the help bar
+	statusMsg   string
+	statusUntil time.Time
+	statusIsErr bool
+
+	// Remembers which file each submitted job came from
+	tracker *JobTracker
+
+	// Options last used on each printer, applied when staging for it
+	printerDefaults *PrinterDefaults
+
+	// Floating overlay state
+	overlay     OverlayKind
+	detailJobID string // System job shown in the job detail overlay
+	detailOpID  string // PrintOperation shown in the job detail overlay
+
+	// Printer status overlay state
+	printerState        printerStateMsg
+	printerStateLoading bool
+
+	// Print history overlay state
+	historyEntries []HistoryEntry // Snapshot taken when the overlay opens, newest first
+	historyCursor  int
+
+	// All-printers overview state
+	printerQueues        printerQueuesMsg
+	printerQueuesLoading bool // A refresh is in flight
+
+	// Printer picker state
+	pickerPurpose   PickerPurpose
+	pickerCursor    int
+	reprintPath     string // File to reprint once a printer is picked
+	reprintName     string
+	reprintCopies   int
+	selectedPrinter string   // Destination for files without their own printer; "" is the system default
+	settings        Settings // Printer and staging choices kept between runs
+
+	// Confirmation overlay state
+	confirmAction  ConfirmAction
+	confirmText    string
+	confirmDir     string // Directory for ConfirmPrintDirectory
+	confirmPrinter string // Printer for ConfirmDuplicateBatch
+
+	// Text prompt overlay state
+	promptAction PromptAction
+	promptTitle  string
+	promptInput  textinput.Model
+	promptErr    string // Validation error shown under the input
+	promptIndex  int    // Staged entry for PromptExtraOptions / PromptNote / PromptSplitJobs
+	promptPath   string // File for PromptRename
+
+	// Files and options of the most recent staged batch, for ctrl+r
+	lastBatch []StagedFile
+
+	// Staged list persistence (persist_staging)
+	savedStaged      []StagedFile // Staged list as last written to staged.json
+	stagedSaveFailed bool         // Saving staged.json failed; reported once
+	stagedSavedAt    time.Time    // Last write attempt, to throttle saves
+
+	// How the next staged batch is submitted
+	printSmallestFirst bool // Smallest file first
+	printSets          int  // Complete sets of the batch to print, one after another
+
+	// Live updates of the browsed directory: fsnotify when available,
+	// otherwise its modification time is checked on every tick
+	dirWatcher   *fsnotify.Watcher
+	watchedDir   string
+	dirPolled    bool // No watcher on watchedDir; poll dirModTime instead
+	dirModTime   time.Time
+	dirReloadSeq int // Debounces reloads; only the latest scheduled one runs
+	listedDir    string
+	listedSizes  map[string]int64 // File sizes in listedDir as of the last load
+	dirCache     *dirCache        // Recent listings, reused while unmodified
+	dirScanning  bool             // A background scan for directory counts is running
+
+	// Active section lists only failed/canceled operations ("F")
+	problemsOnly bool
+
+	// Completed operations are folded into one summary row ("C")
+	collapseCompleted bool
+
+	// Row under the cursor shown wrapped in full instead of truncated ("w")
+	expandedRow string
+
+	// Display toggles
+	hideScrollbar  bool // Reclaim the scrollbar columns on narrow terminals
+	hideExtensions bool // Show file names without extensions in the browser
+	showHidden     bool // List dotfiles in the browser (".")
+	sortMode       SortMode
+	sortDescending bool
+	showMarkedOnly bool // Review mode: list only marked files and dirs containing them
 
 	errorMsg string
 	args     []string
@@ -214,21 +381,47 @@ func initialModel(args []string) model {
 
 	currentDir, _ := os.Getwd()
 
+	sp := spinner.New()
+	sp.Spinner = spinner.MiniDot
+	sp.Style = lipgloss.NewStyle().Foreground(theme.Text)
+
 	m := model{
-		layoutMode:      LayoutSingle,
-		activePane:      PaneQueue,
-		fileFocus:       FocusInput,
-		queueSection:    SectionActive,
-		selected:        make(map[int]bool),
-		markedFiles:     make(map[string]bool),
-		matchedFiles:    make(map[string]bool),
-		dirCursorMemory: make(map[string]int),
-		stagedFiles:     []StagedFile{},
-		textInput:       ti,
-		currentDir:      currentDir,
-		printOps:        []PrintOperation{},
-		helpBar:         NewHelpBar(80), // Initial width, will be updated
-		args:            args,
+		layoutMode:        LayoutSingle,
+		activePane:        PaneQueue,
+		fileFocus:         FocusInput,
+		queueSection:      SectionActive,
+		selected:          make(map[int]bool),
+		pinnedJobs:        make(map[string]bool),
+		markedFiles:       make(map[string]bool),
+		matchedFiles:      make(map[string]bool),
+		dirCursorMemory:   make(map[string]int),
+		queueCursorMemory: make(map[QueueSection]int),
+		stagedFiles:       []StagedFile{},
+		textInput:         ti,
+		currentDir:        currentDir,
+		printOps:          []PrintOperation{},
+		helpBar:           NewHelpBar(80), // Initial width, will be updated
+		mouse:             &mouseLayout{},
+		dirCache:          newDirCache(),
+		spinner:           sp,
+		inFlight:          1, // Init dispatches the first job refresh
+		args:              args,
+	}
+
+	if config.PersistStaging {
+		m.restoreStagedState()
+	}
+	m.helpBar.SetWrap(config.WrapHelpBar)
+	m.hideScrollbar = !config.ShowScrollbar
+
+	// Preferred startup pane from the config
+	if config.StartupPane == "files" {
+		m.activePane = PaneFiles
+		if config.StartupFocus == "list" {
+			m.fileFocus = FocusFileList
+		} else {
+			m.textInput.Focus()
+		}
 	}
 
 	// If args provided, start with files pane focused
@@ -239,46 +432,72 @@ func initialModel(args []string) model {
 		m.textInput.Focus()
 	}
 
+	if config.Offline {
+		m.inFlight = 0 // No initial job refresh
+		m.queueSection = SectionStaged
+	}
+
+	tracker, err := NewJobTracker()
+	m.tracker = tracker
+	m.printerDefaults = loadPrinterDefaults()
+	m.settings = loadSettings()
+	m.selectedPrinter = m.settings.Printer
+	if err != nil {
+		// Read-only home or similar: keep working, but say history won't persist
+		m.setNotice(err.Error(), 10*time.Second)
+	}
+	if configErr != nil {
+		m.setNotice("Config error, using defaults: "+configErr.Error(), 10*time.Second)
+	}
+	if tracker.IsPersistent() && needsOnboarding() {
+		// First launch: explain the staging flow once
+		m.overlay = OverlayOnboarding
+	}
+
 	// Always load directory for split view
+	m.dirWatcher = newDirWatcher()
 	m.loadDirectory()
 
 	// Don't refresh jobs synchronously anymore
 	return m
 }
 
-func (m *model) refreshJobs() {
-	m.jobs = getSystemPrintJobs()
-}
-
 func (m *model) loadDirectory() {
 	m.files = []FileItem{}
 	m.errorMsg = ""
 
 	// Clear matched files, keep marked files
 	m.matchedFiles = make(map[string]bool)
+	m.patternStale = false
 
-	// Read directory contents
-	entries, err := ioutil.ReadDir(m.currentDir)
+	// Read directory contents, or reuse them if unchanged since last time
+	entries, err := m.dirCache.read(m.currentDir)
 	if err != nil {
 		m.errorMsg = fmt.Sprintf("Cannot read directory: %v", err)
+		m.setError(m.errorMsg)
 		return
 	}
 
+	// Drop dotfiles first so every count below matches what's listed
+	if !m.showHidden {
+		visible := make([]os.FileInfo, 0, len(entries))
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), ".") {
+				visible = append(visible, entry)
+			}
+		}
+		entries = visible
+	}
+
 	// Add select/deselect all option at the top
 	printableCount := 0
 	for _, entry := range entries {
-		if !entry.IsDir() {
-			ext := strings.ToLower(filepath.Ext(entry.Name()))
-			for _, pExt := range printableExts {
-				if ext == pExt {
-					printableCount++
-					break
-				}
-			}
+		if !entry.IsDir() && isPrintableName(entry.Name()) {
+			printableCount++
 		}
 	}
 
-	if printableCount > 0 {
+	if printableCount > 0 && !m.showMarkedOnly {
 		m.files = append(m.files, FileItem{
 			Name:        fmt.Sprintf("[Select/Deselect All %d Printable Files]", printableCount),
 			Path:        "TOGGLE_ALL",
@@ -287,28 +506,36 @@ func (m *model) loadDirectory() {
 		})
 	}
 
+	// Sizes from the last listing of this directory show files still growing
+	prevSizes := m.listedSizes
+	if m.listedDir != m.currentDir {
+		prevSizes = nil
+	}
+	m.listedDir = m.currentDir
+	m.listedSizes = make(map[string]int64)
+
 	// Process entries
 	pattern := m.textInput.Value()
+	m.bestMatch = ""
+	bestScore := 0
 	for _, entry := range entries {
 		name := entry.Name()
 		path := filepath.Join(m.currentDir, name)
 
-		// Check if it's printable
-		isPrintable := false
-		if !entry.IsDir() {
-			ext := strings.ToLower(filepath.Ext(name))
-			for _, pExt := range printableExts {
-				if ext == pExt {
-					isPrintable = true
-					break
-				}
-			}
+		if m.showMarkedOnly && !m.markedFiles[path] && !(entry.IsDir() && m.containsMarked(path)) {
+			continue
 		}
 
-		// Check if it matches pattern (visual highlight only)
+		// Check if it's printable
+		isPrintable := !entry.IsDir() && isPrintableName(name)
+
+		// Check if it matches pattern (glob, or fuzzy for plain text)
 		if pattern != "" && isPrintable {
-			if matched, _ := filepath.Match(pattern, name); matched {
+			if score, matched := matchPattern(pattern, name); matched {
 				m.matchedFiles[path] = true
+				if m.bestMatch == "" || score > bestScore {
+					m.bestMatch, bestScore = path, score
+				}
 			}
 		}
 
@@ -318,38 +545,166 @@ func (m *model) loadDirectory() {
 			IsDir:       entry.IsDir(),
 			IsPrintable: isPrintable,
 			Size:        entry.Size(),
+			ModTime:     entry.ModTime(),
+		}
+		if !entry.IsDir() {
+			prevSize, seen := prevSizes[path]
+			if !seen {
+				prevSize = -1
+			}
+			item.Writing = looksBeingWritten(name, entry.Size(), prevSize, entry.ModTime())
+			m.listedSizes[path] = entry.Size()
 		}
 
 		m.files = append(m.files, item)
 	}
 
 	// Sort: special items first, then directories, then files
-	sort.Slice(m.files, func(i, j int) bool {
-		// Keep toggle all at the top
-		if m.files[i].Path == "TOGGLE_ALL" {
-			return true
-		}
-		if m.files[j].Path == "TOGGLE_ALL" {
-			return false
-		}
-		if m.files[i].IsDir != m.files[j].IsDir {
-			return m.files[i].IsDir
-		}
-		return m.files[i].Name < m.files[j].Name
-	})
+	m.sortFiles()
 
 	// Reset cursor if out of bounds
 	if m.fileCursor >= len(m.files) {
 		m.fileCursor = 0
 	}
+
+	m.watchCurrentDir()
+}
+
+// unstageNonMatching removes staged files in the current directory that the
+// current pattern doesn't match: the inverse of staging the matches
+func (m *model) unstageNonMatching() {
+	m.applyPendingPattern()
+	if m.textInput.Value() == "" {
+		m.setStatus("No pattern to match against")
+		return
+	}
+
+	kept := m.stagedFiles[:0]
+	removed := 0
+	for _, file := range m.stagedFiles {
+		if filepath.Dir(file.Path) == m.currentDir && !m.matchedFiles[file.Path] {
+			delete(m.markedFiles, file.Path)
+			removed++
+			continue
+		}
+		kept = append(kept, file)
+	}
+	m.stagedFiles = kept
+	m.clampCursors()
+
+	m.setStatus(fmt.Sprintf("Unstaged %d file(s) not matching %s", removed, m.textInput.Value()))
+}
+
+// reportOfflineStatus shows a finished submission in the status line, since
+// offline mode hides the active section
+func (m *model) reportOfflineStatus(op PrintOperation) {
+	switch op.Status {
+	case StatusSent:
+		m.setStatus("Sent " + op.FileName)
+	case StatusFailed:
+		m.setError(fmt.Sprintf("Failed to print %s: %v", op.FileName, op.Error))
+	}
+}
+
+// invertStagedInDir unstages what's staged from the current directory and
+// stages every printable file here that wasn't
+func (m *model) invertStagedInDir() {
+	wasStaged := make(map[string]bool)
+	kept := m.stagedFiles[:0]
+	for _, file := range m.stagedFiles {
+		if filepath.Dir(file.Path) == m.currentDir {
+			wasStaged[file.Path] = true
+			delete(m.markedFiles, file.Path)
+			continue
+		}
+		kept = append(kept, file)
+	}
+	m.stagedFiles = kept
+
+	added := 0
+	for _, entry := range printableFilesIn(m.currentDir) {
+		path := filepath.Join(m.currentDir, entry.Name())
+		if wasStaged[path] {
+			continue
+		}
+		m.markedFiles[path] = true
+		m.stageFile(entry.Name(), path, m.currentDir, entry.Size())
+		added++
+	}
+
+	if m.showMarkedOnly {
+		m.loadDirectory()
+	}
+	m.clampCursors()
+	m.setStatus(fmt.Sprintf("Inverted selection: +%d staged, -%d unstaged", added, len(wasStaged)))
+}
+
+// toggleHidden shows or hides dotfiles in the browser
+func (m *model) toggleHidden() {
+	m.showHidden = !m.showHidden
+	m.reloadDirectory()
+	if m.showHidden {
+		m.setStatus("Showing hidden files")
+	} else {
+		m.setStatus("Hiding hidden files")
+	}
+}
+
+// containsMarked reports whether any marked file lives somewhere under dir
+func (m model) containsMarked(dir string) bool {
+	prefix := dir + string(filepath.Separator)
+	for path, marked := range m.markedFiles {
+		if marked && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleMarkedOnly switches the browser between the full listing and only
+// marked files, keeping the cursor on the same entry when it's still listed
+func (m *model) toggleMarkedOnly() {
+	current := ""
+	if m.fileCursor < len(m.files) {
+		current = m.files[m.fileCursor].Path
+	}
+
+	m.showMarkedOnly = !m.showMarkedOnly
+	m.loadDirectory()
+
+	m.fileCursor = 0
+	for i, file := range m.files {
+		if file.Path == current {
+			m.fileCursor = i
+			break
+		}
+	}
+
+	if m.showMarkedOnly {
+		m.setStatus(fmt.Sprintf("Showing %d marked item(s)", len(m.files)))
+	}
 }
 
 func (m model) Init() tea.Cmd {
+	if config.Offline {
+		// Stage-and-submit only: no job or printer polling. The tick still
+		// runs for the browser's own upkeep.
+		return tea.Batch(
+			tea.EnterAltScreen,
+			textinput.Blink,
+			tickCmd(),
+			m.spinner.Tick,
+			waitForDirChange(m.dirWatcher),
+		)
+	}
 	return tea.Batch(
 		tea.EnterAltScreen,
 		textinput.Blink,
 		tickCmd(),
 		refreshJobsCmd(),  // Initial job refresh
+		refreshPrintersCmd(),
+		m.spinner.Tick,
+		waitForDirChange(m.dirWatcher),
 	)
 }
 
@@ -361,6 +716,19 @@ func (m *model) updateLayoutMode() {
 		helpBarHeight = 1  // Space needed for help bar
 	)
 
+	// A manual override wins over the size-based choice
+	switch m.layoutOverride {
+	case LayoutForceSingle:
+		m.layoutMode = LayoutSingle
+		return
+	case LayoutForceHorizontal:
+		m.layoutMode = LayoutHorizontal
+		return
+	case LayoutForceVertical:
+		m.layoutMode = LayoutVertical
+		return
+	}
+
 	// Account for help bar in height calculations
 	availableHeight := m.height - helpBarHeight - 1
 
@@ -378,7 +746,39 @@ func (m *model) updateLayoutMode() {
 	}
 }
 
+// applyLayout recomputes the layout and everything sized from it
+func (m *model) applyLayout() {
+	// Determine layout mode based on terminal size
+	m.updateLayoutMode()
+
+	// Update text input width based on layout
+	if m.layoutMode == LayoutHorizontal {
+		m.textInput.Width = (m.width / 2) - 6
+	} else {
+		m.textInput.Width = m.width - 6
+	}
+	if m.textInput.Width > 100 {
+		m.textInput.Width = 100
+	}
+
+	// Keep the current selection valid for the new pane sizes
+	m.clampCursors()
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	if um, ok := updated.(model); ok {
+		if config.Offline {
+			// The active section is hidden offline; keep the queue on staging
+			um.queueSection = SectionStaged
+		}
+		um.syncStagedState()
+		updated = um
+	}
+	return updated, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -388,19 +788,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// An open overlay consumes all keys
+		if m.overlay != OverlayNone {
+			return m.updateOverlay(msg)
+		}
+
 		// Handle global shortcuts first
 		switch msg.String() {
 		case "ctrl+c":
-			return m, tea.Quit
+			cmd := m.requestQuit()
+			return m, cmd
 
 		case "tab":
 			// Move to next pane
 			if m.layoutMode != LayoutSingle {
 				switch m.activePane {
 				case PaneQueue:
+					m.rememberQueueCursor()
 					m.activePane = PaneFiles
 				case PaneFiles:
 					m.activePane = PaneQueue
+					m.restoreQueueSection(m.queueSection)
 				}
 			}
 			return m, nil
@@ -410,57 +818,106 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.layoutMode != LayoutSingle {
 				switch m.activePane {
 				case PaneQueue:
+					m.rememberQueueCursor()
 					m.activePane = PaneFiles
 				case PaneFiles:
 					m.activePane = PaneQueue
+					m.restoreQueueSection(m.queueSection)
 				}
 			}
 			return m, nil
 
+		case "ctrl+n", "ctrl+o":
+			// Step focus through active → staged → input → list, whatever the cursor position
+			step := 1
+			if msg.String() == "ctrl+o" {
+				step = -1
+			}
+			cmd := m.cycleFocus(step)
+			return m, cmd
+
 		case "P":
 			// Send all staged files to printer from any context
-			if len(m.stagedFiles) > 0 {
-				// Store start index before adding new operations
-				startIndex := len(m.printOps)
-
-				// Create print operations and commands for each staged file
-				var printCmds []tea.Cmd
-				for _, file := range m.stagedFiles {
-					copies := file.Copies
-					if copies < 1 {
-						copies = 1
-					}
-					opID := fmt.Sprintf("%s-%d", file.Path, time.Now().UnixNano())
-					op := PrintOperation{
-						ID:        opID,
-						FilePath:  file.Path,
-						FileName:  file.Name,
-						Status:    StatusSending,  // Start as sending since we submit immediately
-						StartedAt: time.Now(),
-						UpdatedAt: time.Now(),
-					}
-					m.printOps = append(m.printOps, op)
+			cmd := m.requestPrintStaged(false)
+			return m, cmd
+
+		case "ctrl+g":
+			// Jump to the first staged file from anywhere
+			if len(m.stagedFiles) == 0 {
+				m.setStatus("Nothing staged")
+				return m, nil
+			}
+			m.activePane = PaneQueue
+			m.queueSection = SectionStaged
+			m.stagedCursor = 0
+			m.textInput.Blur()
+			return m, nil
+
+		case "E":
+			// Export the queue, staging and history as a markdown report
+			if !m.isTyping() {
+				m.exportReport()
+				return m, nil
+			}
+
+		case "ctrl+r":
+			// Stage the most recent batch again
+			m.restageLastBatch()
+			return m, nil
+
+		case "D":
+			// Toggle safe mode: submissions become no-ops, for demos and training
+			if !m.isTyping() {
+				safeMode.Store(!safeMode.Load())
+				if safeMode.Load() {
+					m.setStatus("Safe mode on: nothing will be printed")
+				} else {
+					m.setStatus("Safe mode off: printing for real")
+				}
+				return m, nil
+			}
 
-					// Submit the print job - it runs async in its own goroutine
-					printCmds = append(printCmds, submitPrintJobCmd(opID, file.Path, copies))
+		case "S":
+			// Drop into a shell in the browsed directory; exit it to come back
+			if !m.isTyping() {
+				cmd := shellCmd(m.currentDir)
+				return m, cmd
+			}
 
-					delete(m.markedFiles, file.Path)
+		case "W":
+			// Wrap the help bar onto two lines instead of truncating it
+			if !m.isTyping() {
+				m.helpBar.SetWrap(!m.helpBar.Wrapping())
+				if m.helpBar.Wrapping() {
+					m.setStatus("Help bar wraps onto two lines")
+				} else {
+					m.setStatus("Help bar fits on one line")
 				}
-				
-				// Clear staged files
-				m.stagedFiles = []StagedFile{}
-				m.stagedCursor = 0
+				return m, nil
+			}
 
-				// Switch to queue pane to show progress
-				m.activePane = PaneQueue
-				m.queueSection = SectionActive  // Focus on the active jobs section
-				// Position cursor at the first newly added operation
-				m.activeCursor = len(m.jobs) + startIndex  // Position after system jobs
-				
-				// Use Batch to run all commands concurrently
-				return m, tea.Batch(printCmds...)
+		case "L":
+			// Cycle manual layout override (not while typing a pattern)
+			if !m.isTyping() {
+				m.layoutOverride = (m.layoutOverride + 1) % (LayoutForceVertical + 1)
+				m.applyLayout()
+				m.setStatus("Layout: " + m.layoutOverride.String())
+				return m, nil
+			}
+
+		case "z":
+			// Zoom the active pane to full screen, keeping the other pane's state
+			if !m.isTyping() && m.layoutMode != LayoutSingle {
+				m.zoomed = !m.zoomed
+				return m, nil
+			}
+
+		case "B":
+			// Toggle scrollbars (not while typing a pattern)
+			if !m.isTyping() {
+				m.hideScrollbar = !m.hideScrollbar
+				return m, nil
 			}
-			return m, nil
 
 		case "X":
 			// Clear all staged files from any context
@@ -482,34 +939,78 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 
-		// Determine layout mode based on terminal size
-		m.updateLayoutMode()
-
-		// Update text input width based on layout
-		if m.layoutMode == LayoutHorizontal {
-			m.textInput.Width = (m.width / 2) - 6
-		} else {
-			m.textInput.Width = m.width - 6
-		}
-		if m.textInput.Width > 100 {
-			m.textInput.Width = 100
-		}
+		m.applyLayout()
 
 	case tickMsg:
 		// Always continue ticking and refresh
 		// The timeout in getSystemPrintJobs prevents hanging
+		m.pollCurrentDir()
+		m.recheckWritingFiles()
+		scan := m.scanNearbyDirs()
+		if config.Offline {
+			// Jobs aren't polled offline; only the browser needs the tick
+			return m, tea.Batch(tickCmd(), scan)
+		}
+		refresh := pollJobsCmd()
+		var queues tea.Cmd
+		if m.overlay == OverlayPrinterQueues && !m.printerQueuesLoading {
+			m.printerQueuesLoading = true
+			queues = printerQueuesCmd()
+		}
 		return m, tea.Batch(
 			tickCmd(),          // Continue ticking
-			refreshJobsCmd(),   // Refresh jobs in background
+			refresh,            // Refresh jobs in background
+			queues,             // Keep the all-printers overview current
+			scan,               // Count printable files in directories on screen
 		)
 
+	case dirsScannedMsg:
+		m.dirScanning = false
+		for dir, listing := range msg.listings {
+			m.dirCache.store(dir, listing)
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case jobCanceledMsg:
+		m.untrackCmd()
+		if msg.err != nil {
+			m.setError(strings.TrimSpace(msg.err.Error()))
+			return m, nil
+		}
+		for i := range m.printOps {
+			if msg.opID != "" && m.printOps[i].ID == msg.opID {
+				m.printOps[i].Status = StatusCanceled
+				m.printOps[i].UpdatedAt = time.Now()
+			}
+		}
+		m.setStatus(fmt.Sprintf("Canceled job %s", msg.jobID))
+		refresh := m.trackCmd(refreshJobsCmd())
+		return m, refresh
+
 	case jobsRefreshedMsg:
+		if !msg.polled {
+			m.untrackCmd()
+		}
+		if msg.err != nil {
+			// Keep the last known queue: an unreadable queue isn't an empty
+			// one, and reading it as empty would mark every sent job printed
+			return m, nil
+		}
 		// Update jobs from async refresh
 		m.jobs = msg.jobs
+		m.sortPinnedJobs()
 
 		// Clean up print operations that are successfully sent and no longer in system queue
 		var cleanedOps []PrintOperation
@@ -525,15 +1026,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				for _, job := range m.jobs {
 					if job.ID == op.SystemJobID {
 						keepOp = true
+						op.SeenInQueue = true
 						break
 					}
 				}
 			}
 
-			// Keep failed, canceled, or still processing operations
+			// Keep failed, canceled, completed or still processing operations,
+			// and dry-run ones so their command can be read
 			if !keepOp && (op.Status == StatusFailed || op.Status == StatusCanceled ||
-				op.Status == StatusPending || op.Status == StatusSending) {
+				op.Status == StatusCompleted || op.Status == StatusPending || op.Status == StatusSending ||
+				op.Command != "") {
+				keepOp = true
+			}
+
+			// A sent job that left the queue has finished printing. A refresh
+			// started before the job was queued may not list it yet, so jobs
+			// never seen in the queue get a grace period.
+			if !keepOp && config.TrackCompletion && op.Status == StatusSent && op.SystemJobID != "" {
 				keepOp = true
+				if op.SeenInQueue || time.Since(op.UpdatedAt) > completionGrace {
+					op.Status = StatusCompleted
+					op.UpdatedAt = time.Now()
+				}
 			}
 
 			if keepOp {
@@ -550,20 +1065,115 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		
 		return m, nil
 
+	case shellExitedMsg:
+		if msg.err != nil {
+			m.setError("Shell: " + msg.err.Error())
+		}
+		// Files may have been created, moved or deleted from the shell
+		m.loadDirectory()
+		return m, nil
+
+	case dirChangedMsg:
+		// Keep listening; the reload waits for the changes to settle
+		cmd := m.scheduleDirReload()
+		return m, tea.Batch(cmd, waitForDirChange(m.dirWatcher))
+
+	case dirReloadMsg:
+		if msg.seq == m.dirReloadSeq {
+			m.reloadDirectory()
+		}
+		return m, nil
+
+	case patternReloadMsg:
+		if msg.seq == m.patternReloadSeq {
+			m.applyPendingPattern()
+		}
+		return m, nil
+
+	case pagerExitedMsg:
+		if msg.err != nil {
+			m.setError("Pager: " + msg.err.Error())
+		}
+		return m, nil
+
+	case clearSentMsg:
+		// Failed/canceled operations stay until dismissed
+		for _, op := range m.printOps {
+			if op.ID == msg.OpID && (op.Status == StatusSent || op.Status == StatusCompleted) {
+				m.removeOperation(op.ID)
+				break
+			}
+		}
+		return m, nil
+
+	case failoverReadyMsg:
+		cmd := m.applyFailover(msg)
+		return m, cmd
+
+	case printersRefreshedMsg:
+		m.printers = msg.printers
+		m.clampPickerCursor()
+		m.checkSelectedPrinter()
+		return m, nil
+
+	case printerQueuesMsg:
+		m.printerQueues = msg
+		m.printerQueuesLoading = false
+		return m, nil
+
+	case printerStateMsg:
+		if msg.Name == m.printerState.Name {
+			m.printerState = msg
+			m.printerStateLoading = false
+		}
+		return m, nil
+
 	case PrintStatusMsg:
+		if msg.Status != StatusSending && msg.Status != StatusPending {
+			m.untrackCmd()
+		}
+
 		// Update print operation status and store CUPS job ID
 		for i := range m.printOps {
 			if m.printOps[i].ID == msg.FileID {
+				if msg.Status == StatusFailed && m.printOps[i].Status != StatusFailed {
+					cmds = append(cmds, failureAlertCmd())
+				}
 				m.printOps[i].Status = msg.Status
 				m.printOps[i].Error = msg.Error
 				m.printOps[i].UpdatedAt = time.Now()
 				if msg.SystemJobID != "" {
 					m.printOps[i].SystemJobID = msg.SystemJobID
 				}
+				if msg.Command != "" {
+					m.printOps[i].Command = msg.Command
+				}
+				if msg.Status == StatusSent && msg.SystemJobID != "" {
+					m.trackJob(m.printOps[i])
+				}
+				if msg.Status == StatusSent && msg.Command == "" {
+					op := m.printOps[i]
+					m.printerDefaults.Remember(op.Printer, PrintOptions{
+						Copies: op.Copies,
+						Duplex: op.Duplex,
+						Color:  op.Color,
+						Extra:  op.Extra,
+					})
+					m.rememberPrintSettings(op)
+					m.recordHistory(op)
+					if config.ClearSentAfter > 0 {
+						cmds = append(cmds, clearSentCmd(op.ID))
+					}
+				}
+				if config.Offline {
+					// No active section to watch, so report the outcome here
+					m.reportOfflineStatus(m.printOps[i])
+				}
 				break
 			}
 		}
-		return m, nil
+		m.prunePrintOps()
+		return m, tea.Batch(cmds...)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -575,10 +1185,12 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// In split view, q switches to queue pane
 		if m.layoutMode != LayoutSingle && m.activePane == PaneFiles {
 			m.activePane = PaneQueue
+			m.restoreQueueSection(m.queueSection)
 			m.textInput.Blur()
 			return m, nil
 		}
-		return m, tea.Quit
+		cmd := m.requestQuit()
+		return m, cmd
 
 	case "up", "k":
 		if m.queueSection == SectionActive {
@@ -592,6 +1204,7 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				// Move to active section
 				m.resetStagedPendingRemove(-1) // Reset all
+				m.rememberQueueCursor()
 				m.queueSection = SectionActive
 				actualJobCount := m.getActualJobCount()
 				if actualJobCount > 0 {
@@ -609,10 +1222,12 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				relativeStagedFiles := m.getRelativeStagedFiles()
 				if len(relativeStagedFiles) > 0 {
 					// Move to staged section
+					m.rememberQueueCursor()
 					m.queueSection = SectionStaged
 					m.stagedCursor = 0
 				} else if m.layoutMode != LayoutSingle {
 					// No staged files, go to files pane
+					m.rememberQueueCursor()
 					m.activePane = PaneFiles
 					m.fileFocus = FocusInput
 					m.textInput.Focus()
@@ -627,6 +1242,7 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else if m.layoutMode != LayoutSingle {
 				// At bottom of staged, move to files pane
 				m.resetStagedPendingRemove(-1) // Reset all
+				m.rememberQueueCursor()
 				m.activePane = PaneFiles
 				m.fileFocus = FocusInput
 				m.textInput.Focus()
@@ -646,7 +1262,7 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			newCursor := m.stagedCursor - 5
 			if newCursor < 0 {
 				// Switch to active section if we have jobs
-				if len(m.jobs) > 0 {
+				if m.getActualJobCount() > 0 {
 					m.queueSection = SectionActive
 					m.activeCursor = 0
 				} else {
@@ -669,7 +1285,7 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.queueSection = SectionStaged
 					m.stagedCursor = 0
 				} else {
-					m.activeCursor = len(m.jobs) - 1
+					m.activeCursor = actualJobCount - 1
 				}
 			} else {
 				m.activeCursor = newCursor
@@ -688,6 +1304,7 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "a", "f":
 		// Switch to files pane
+		m.rememberQueueCursor()
 		if m.layoutMode == LayoutSingle {
 			m.activePane = PaneFiles
 			m.fileFocus = FocusInput
@@ -704,83 +1321,41 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "p":
 		// Focus on queue pane to see print operations
 		m.activePane = PaneQueue
-		m.queueSection = SectionActive
+		m.restoreQueueSection(SectionActive)
 
 	case "x":
-		if m.queueSection == SectionActive {
-			// Build the deduplicated list to find what's at the cursor
-			itemIndex := 0
-			handled := false
-
-			// First check system jobs
-			for _, job := range m.jobs {
-				if itemIndex == m.activeCursor {
-					// Cancel system job
-					cancelPrintJob(job.ID)
-					// Also cancel our tracking if we have it (match by job ID)
-					for j := range m.printOps {
-						if m.printOps[j].SystemJobID == job.ID {
-							m.printOps[j].Status = StatusCanceled
-							m.printOps[j].UpdatedAt = time.Now()
-							break
-						}
-					}
-					handled = true
-					return m, refreshJobsCmd()
+		if m.queueSection == SectionActive && m.onCompletedSummary() {
+			// Dismiss every folded completed operation at once
+			m.clearCompleted()
+		} else if m.queueSection == SectionActive {
+			job, op := m.activeItemAtCursor()
+			if job != nil {
+				// Cancel by the system job ID, so jobs submitted outside this
+				// app (no operation or source path) can be canceled too
+				opID := ""
+				if op != nil {
+					opID = op.ID
 				}
-				itemIndex++
+				m.setStatus(fmt.Sprintf("Canceling job %s…", job.ID))
+				cancel := m.trackCmd(cancelJobCmd(job.ID, opID))
+				return m, cancel
 			}
-
-			// Then check unmatched print operations
-			if !handled {
-				for i, op := range m.printOps {
-					// Skip if this operation has a system job (match by job ID)
-					hasSystemJob := false
-					if op.SystemJobID != "" {
-						for _, job := range m.jobs {
-							if job.ID == op.SystemJobID {
-								hasSystemJob = true
-								break
-							}
-						}
-					}
-
-					if !hasSystemJob && op.Status != StatusSent {
-						if itemIndex == m.activeCursor {
-							if op.Status == StatusSending || op.Status == StatusPending {
-								// Cancel active operation
-								m.printOps[i].Status = StatusCanceled
-								m.printOps[i].UpdatedAt = time.Now()
-							} else if op.Status == StatusFailed || op.Status == StatusCanceled {
-								// Remove completed/failed/canceled operation
-								m.printOps = append(m.printOps[:i], m.printOps[i+1:]...)
-								actualJobCount := m.getActualJobCount()
-								if m.activeCursor >= actualJobCount && m.activeCursor > 0 {
-									m.activeCursor--
-								}
-							}
-							handled = true
-							break
-						}
-						itemIndex++
-					}
+			if op != nil {
+				if op.Status == StatusSending || op.Status == StatusPending {
+					// Cancel active operation
+					op.Status = StatusCanceled
+					op.UpdatedAt = time.Now()
+				} else {
+					// Remove finished (failed/canceled/completed) operation
+					m.removeOperation(op.ID)
 				}
 			}
 		} else if m.queueSection == SectionStaged {
 			// Get relative files for current directory
 			relativeStagedFiles := m.getRelativeStagedFiles()
 			if m.stagedCursor < len(relativeStagedFiles) {
-				// Remove from staged and unmark
-				filePath := relativeStagedFiles[m.stagedCursor].Path
-				delete(m.markedFiles, filePath)
-				
-				// Remove from the main staged files list
-				for i := len(m.stagedFiles) - 1; i >= 0; i-- {
-					if m.stagedFiles[i].Path == filePath {
-						m.stagedFiles = append(m.stagedFiles[:i], m.stagedFiles[i+1:]...)
-						break
-					}
-				}
+				// Remove only this entry; the file stays marked while other jobs of it remain
+				m.removeStagedAt(m.stagedCursor)
 				
 				// Adjust cursor for relative list
 				newRelativeFiles := m.getRelativeStagedFiles()
@@ -791,73 +1366,192 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "o":
-		if m.queueSection == SectionActive {
-			totalJobs := len(m.jobs) + len(m.printOps)
-			if m.activeCursor < totalJobs {
-				if m.activeCursor < len(m.jobs) {
-					// For system jobs, find matching PrintOperation by job ID
-					job := m.jobs[m.activeCursor]
-					filePath := m.findFilePathByJobID(job.ID)
-					openFile(filePath)
-				} else {
-					opIndex := m.activeCursor - len(m.jobs)
-					openFile(m.printOps[opIndex].FilePath)
-				}
-			}
-		} else if m.queueSection == SectionStaged {
-			relativeStagedFiles := m.getRelativeStagedFiles()
-			if m.stagedCursor < len(relativeStagedFiles) {
-				openFile(relativeStagedFiles[m.stagedCursor].Path)
-			}
-		}
+		m.reportOpenError(openFile(m.sourcePathAtCursor()))
 
 	case "O":
+		m.reportOpenError(openFolder(m.sourcePathAtCursor()))
+
+	case "s":
+		// Print staged files smallest first so quick jobs aren't stuck behind a big one
+		if m.queueSection == SectionStaged {
+			cmd := m.requestPrintStaged(true)
+			return m, cmd
+		}
+
+	case "b":
+		// Print several complete sets of the staged batch
+		if m.queueSection == SectionStaged {
+			cmd := m.openBatchSetsPrompt()
+			return m, cmd
+		}
+
+	case "V":
+		// Read a text file in $PAGER; works over SSH where o can't open anything
+		cmd := m.viewInPager()
+		return m, cmd
+
+	case "I":
+		// Show why the printer is stopped or stalled, straight from CUPS
+		cmd := m.openPrinterState()
+		return m, cmd
+
+	case "Q":
+		// Watch every printer's queue at once, without switching printers
+		cmd := m.openPrinterQueues()
+		return m, cmd
+
+	case "H":
+		// Recent prints, to stage one again
+		m.openHistory()
+		return m, nil
+
+	case "g":
+		// Choose the printer staged files are sent to
+		cmd := m.openPrinterPicker(PickDestination)
+		return m, cmd
+
+	case "d":
+		// Stage the file as several separate jobs instead of copies in one job
+		if m.queueSection == SectionStaged {
+			cmd := m.openSplitJobsPrompt()
+			return m, cmd
+		}
+
+	case "+":
+		// Send the same batch to a second printer as well
+		if m.queueSection == SectionStaged {
+			cmd := m.openDuplicateBatchPicker()
+			return m, cmd
+		}
+
+	case "#":
+		// Limit the staged file to a page range
+		if m.queueSection == SectionStaged {
+			cmd := m.editPages()
+			return m, cmd
+		}
+
+	case "G":
+		// Cycle printer default → grayscale → color
+		if m.queueSection == SectionStaged {
+			m.cycleColor()
+		}
+
+	case "v":
+		// Cycle one-sided → two-sided long edge → short edge
+		if m.queueSection == SectionStaged {
+			m.cycleDuplex()
+		}
+
+	case "n":
+		// Tag the staged file with a short note
+		if m.queueSection == SectionStaged {
+			cmd := m.editNote()
+			return m, cmd
+		}
+
+	case "e":
+		// Edit raw CUPS options for the staged file
+		if m.queueSection == SectionStaged {
+			cmd := m.editExtraOptions()
+			return m, cmd
+		}
+
+	case "w":
+		// Expand/collapse the truncated row under the cursor
+		m.toggleExpandedRow()
+		return m, nil
+
+	case "F":
+		// Show only failed/canceled operations for triage
+		m.toggleProblemsOnly()
+		return m, nil
+
+	case "C":
+		// Fold completed operations into one "✓ N printed" row, or list them again
+		m.collapseCompleted = !m.collapseCompleted
+		m.clampCursors()
+		return m, nil
+
+	case "R":
+		// Retry the failed/canceled operation under the cursor
 		if m.queueSection == SectionActive {
-			totalJobs := len(m.jobs) + len(m.printOps)
-			if m.activeCursor < totalJobs {
-				if m.activeCursor < len(m.jobs) {
-					// For system jobs, find matching PrintOperation by job ID
-					job := m.jobs[m.activeCursor]
-					filePath := m.findFilePathByJobID(job.ID)
-					openFolder(filePath)
-				} else {
-					opIndex := m.activeCursor - len(m.jobs)
-					openFolder(m.printOps[opIndex].FilePath)
-				}
+			cmd := m.retryOperation()
+			return m, cmd
+		}
+
+	case "]", "[":
+		// Step through failed/canceled operations like errors in an editor
+		if m.queueSection == SectionActive {
+			step := 1
+			if msg.String() == "[" {
+				step = -1
 			}
-		} else if m.queueSection == SectionStaged && m.stagedCursor < len(m.stagedFiles) {
-			openFolder(m.stagedFiles[m.stagedCursor].Path)
+			m.jumpToProblem(step)
+		}
+
+	case "N":
+		// Resubmit the failed operation to the next ready printer
+		if m.queueSection == SectionActive {
+			cmd := m.retryOnNextPrinter()
+			return m, cmd
+		}
+
+	case "y":
+		// Copy the lp command for the staged file or operation under the cursor
+		m.copyPrintCommand()
+		return m, nil
+
+	case "c":
+		// Print all staged images as one contact sheet page
+		if m.queueSection == SectionStaged {
+			return m, m.printContactSheet()
+		}
+
+	case "T":
+		// Print a test page to check the printer is alive
+		return m, m.printTestPage()
+
+	case "t":
+		// Pin/unpin the selected system job to the top of the list
+		if m.queueSection == SectionActive {
+			m.togglePinnedJob()
+		}
+
+	case "i":
+		// Show details for the selected active job
+		if m.queueSection == SectionActive {
+			m.openJobDetail()
 		}
 
 	case "r":
-		// Refresh jobs asynchronously
-		return m, refreshJobsCmd()
+		if config.Offline {
+			m.setStatus("Offline: job polling is disabled")
+			return m, nil
+		}
+		// Refresh jobs and rediscover printers asynchronously
+		refresh := m.trackCmd(refreshJobsCmd())
+		return m, tea.Batch(refresh, refreshPrintersCmd())
 
 	case "left", "h":
 		if m.queueSection == SectionStaged {
 			relativeStagedFiles := m.getRelativeStagedFiles()
 			if m.stagedCursor < len(relativeStagedFiles) {
-				// Find the actual staged file
-				file := relativeStagedFiles[m.stagedCursor]
-				for i := range m.stagedFiles {
-					if m.stagedFiles[i].Path == file.Path {
-						if m.stagedFiles[i].PendingRemove {
-							// Second left press - remove the file
-							delete(m.markedFiles, file.Path)
-							m.stagedFiles = append(m.stagedFiles[:i], m.stagedFiles[i+1:]...)
-							newRelativeFiles := m.getRelativeStagedFiles()
-							if m.stagedCursor >= len(newRelativeFiles) && m.stagedCursor > 0 {
-								m.stagedCursor--
-							}
-						} else if m.stagedFiles[i].Copies > 1 {
-							// Decrease copies
-							m.stagedFiles[i].Copies--
-						} else {
-							// At 1 copy, set pending remove
-							m.stagedFiles[i].PendingRemove = true
-						}
-						break
+				// Entries map 1:1 to the relative list; a path may be staged more than once
+				i := m.stagedCursor
+				if m.stagedFiles[i].PendingRemove {
+					// Second left press - remove the entry
+					m.removeStagedAt(i)
+					newRelativeFiles := m.getRelativeStagedFiles()
+					if m.stagedCursor >= len(newRelativeFiles) && m.stagedCursor > 0 {
+						m.stagedCursor--
 					}
+				} else if m.stagedFiles[i].Copies > 1 {
+					// Decrease copies
+					m.stagedFiles[i].Copies--
+				} else {
+					// At 1 copy, set pending remove
+					m.stagedFiles[i].PendingRemove = true
 				}
 			}
 		}
@@ -866,22 +1560,22 @@ func (m model) updateQueuePane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.queueSection == SectionStaged {
 			relativeStagedFiles := m.getRelativeStagedFiles()
 			if m.stagedCursor < len(relativeStagedFiles) {
-				file := relativeStagedFiles[m.stagedCursor]
-				for i := range m.stagedFiles {
-					if m.stagedFiles[i].Path == file.Path {
-						if m.stagedFiles[i].PendingRemove {
-							// Cancel pending remove, back to 1
-							m.stagedFiles[i].PendingRemove = false
-						} else {
-							// Increase copies
-							m.stagedFiles[i].Copies++
-						}
-						break
-					}
+				i := m.stagedCursor
+				if m.stagedFiles[i].PendingRemove {
+					// Cancel pending remove, back to 1
+					m.stagedFiles[i].PendingRemove = false
+				} else {
+					// Increase copies
+					m.stagedFiles[i].Copies++
 				}
 			}
 		}
 
+	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if m.queueSection == SectionStaged {
+			m.setCopiesFromDigit(msg.String())
+		}
+
 	case " ":
 		if m.queueSection == SectionActive && m.activeCursor < len(m.jobs) {
 			if m.selected[m.activeCursor] {
@@ -907,16 +1601,19 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Switch back to queue pane
 			if m.layoutMode == LayoutSingle {
 				m.activePane = PaneQueue
+				m.restoreQueueSection(m.queueSection)
 				m.textInput.Blur()
 				return m, nil
 			}
 			// In split view, just switch focus
 			m.activePane = PaneQueue
+			m.restoreQueueSection(m.queueSection)
 			m.textInput.Blur()
 			return m, nil
 
 		case "enter":
 			// Enter stages all matched files
+			m.applyPendingPattern()
 			if m.textInput.Value() != "" {
 				for path := range m.matchedFiles {
 					if !m.markedFiles[path] {
@@ -924,14 +1621,7 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						for _, f := range m.files {
 							if f.Path == path && f.IsPrintable {
 								m.markedFiles[path] = true
-								m.stagedFiles = append(m.stagedFiles, StagedFile{
-									Name:       f.Name,
-									Path:       f.Path,
-									StagedFrom: m.currentDir,
-									Size:       f.Size,
-									AddedAt:    time.Now(),
-									Copies:     1,
-								})
+								m.stageFile(f.Name, f.Path, m.currentDir, f.Size)
 								break
 							}
 						}
@@ -960,14 +1650,19 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.layoutMode != LayoutSingle {
 				m.activePane = PaneQueue
 				m.textInput.Blur()
-				// Go to bottom of staged or active section
+				if len(m.queueCursorMemory) > 0 {
+					// Back to where the queue was left
+					m.restoreQueueSection(m.queueSection)
+					return m, nil
+				}
+				// First visit: go to bottom of staged or active section
 				relativeStagedFiles := m.getRelativeStagedFiles()
 				if len(relativeStagedFiles) > 0 {
 					m.queueSection = SectionStaged
 					m.stagedCursor = len(relativeStagedFiles) - 1
-				} else if len(m.jobs) > 0 {
+				} else if count := m.getActualJobCount(); count > 0 {
 					m.queueSection = SectionActive
-					m.activeCursor = len(m.jobs) - 1
+					m.activeCursor = count - 1
 				}
 				return m, nil
 			}
@@ -979,7 +1674,8 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			oldValue := m.textInput.Value()
 			m.textInput, cmd = m.textInput.Update(msg)
 			if m.textInput.Value() != oldValue {
-				m.loadDirectory()
+				reload := m.schedulePatternReload()
+				return m, tea.Batch(cmd, reload)
 			}
 			return m, cmd
 		}
@@ -993,13 +1689,7 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			file := m.files[m.fileCursor]
 			if file.IsPrintable && m.markedFiles[file.Path] {
 				// Unmark file and remove from staged
-				delete(m.markedFiles, file.Path)
-				for i := len(m.stagedFiles) - 1; i >= 0; i-- {
-					if m.stagedFiles[i].Path == file.Path {
-						m.stagedFiles = append(m.stagedFiles[:i], m.stagedFiles[i+1:]...)
-						break
-					}
-				}
+				m.unstagePath(file.Path)
 			}
 		}
 		return m, nil
@@ -1008,11 +1698,13 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Switch back to queue pane
 		if m.layoutMode == LayoutSingle {
 			m.activePane = PaneQueue
+			m.restoreQueueSection(m.queueSection)
 			m.textInput.Blur()
 			return m, nil
 		}
 		// In split view, just switch focus
 		m.activePane = PaneQueue
+		m.restoreQueueSection(m.queueSection)
 		m.textInput.Blur()
 		return m, nil
 
@@ -1021,22 +1713,15 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			file := m.files[m.fileCursor]
 			if file.IsDir {
 				// Navigate into directory
-				m.currentDir = file.Path
-				m.fileCursor = 0
-				m.loadDirectory()
+				if m.changeDirectory(file.Path) {
+					m.fileCursor = 0
+				}
 			} else if file.IsPrintable {
 				// Stage single file if not already staged
 				if !m.markedFiles[file.Path] {
 					m.markedFiles[file.Path] = true
 					// Add to staged files
-					m.stagedFiles = append(m.stagedFiles, StagedFile{
-						Name:       file.Name,
-						Path:       file.Path,
-						StagedFrom: m.currentDir,
-						Size:       file.Size,
-						AddedAt:    time.Now(),
-						Copies:     1,
-					})
+					m.stageFile(file.Name, file.Path, m.currentDir, file.Size)
 				}
 			}
 		}
@@ -1101,9 +1786,9 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			
 			// Go to parent directory
 			parentDir := filepath.Dir(m.currentDir)
-			m.currentDir = parentDir
-			
-			m.loadDirectory()
+			if !m.changeDirectory(parentDir) {
+				return m, nil
+			}
 			
 			// Try to position cursor on the directory we just left
 			foundPrevDir := false
@@ -1142,7 +1827,9 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.dirCursorMemory[m.currentDir] = m.fileCursor
 				
 				// Navigate into directory
-				m.currentDir = file.Path
+				if !m.changeDirectory(file.Path) {
+					return m, nil
+				}
 				
 				// Restore cursor position if we've been to this directory before
 				if savedCursor, exists := m.dirCursorMemory[file.Path]; exists {
@@ -1151,8 +1838,6 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.fileCursor = 0
 				}
 				
-				m.loadDirectory()
-				
 				// Ensure cursor is within bounds after loading
 				if m.fileCursor >= len(m.files) {
 					m.fileCursor = len(m.files) - 1
@@ -1164,24 +1849,11 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// For printable files, right arrow acts like space (mark/unmark)
 				if m.markedFiles[file.Path] {
 					// Unmark and remove from staged
-					delete(m.markedFiles, file.Path)
-					for i := len(m.stagedFiles) - 1; i >= 0; i-- {
-						if m.stagedFiles[i].Path == file.Path {
-							m.stagedFiles = append(m.stagedFiles[:i], m.stagedFiles[i+1:]...)
-							break
-						}
-					}
+					m.unstagePath(file.Path)
 				} else {
 					// Mark and add to staged
 					m.markedFiles[file.Path] = true
-					m.stagedFiles = append(m.stagedFiles, StagedFile{
-						Name:       file.Name,
-						Path:       file.Path,
-						StagedFrom: m.currentDir,
-						Size:       file.Size,
-						AddedAt:    time.Now(),
-						Copies:     1,
-					})
+					m.stageFile(file.Name, file.Path, m.currentDir, file.Size)
 				}
 			}
 		}
@@ -1190,7 +1862,92 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "p":
 		// Focus on queue pane to see print operations
 		m.activePane = PaneQueue
-		m.queueSection = SectionActive
+		m.restoreQueueSection(SectionActive)
+		return m, nil
+
+	case "R":
+		// Stage every printable file in the directory tree under the cursor
+		if m.fileFocus == FocusFileList && m.fileCursor < len(m.files) && m.files[m.fileCursor].IsDir &&
+			m.files[m.fileCursor].Name != ".." {
+			m.stageTree(m.files[m.fileCursor].Path)
+		}
+		return m, nil
+
+	case "ctrl+p":
+		// Print every printable file in the directory under the cursor, bypassing staging
+		if m.fileFocus == FocusFileList && m.fileCursor < len(m.files) && m.files[m.fileCursor].IsDir {
+			dir := m.files[m.fileCursor]
+			count := len(printableFilesIn(dir.Path))
+			if count == 0 {
+				m.setStatus(fmt.Sprintf("No printable files in %s", dir.Name))
+				return m, nil
+			}
+			m.confirmDir = dir.Path
+			m.openConfirm(ConfirmPrintDirectory, fmt.Sprintf("Print %d file(s) from %s now?", count, dir.Name))
+		}
+		return m, nil
+
+	case "e":
+		// Toggle showing file extensions (display only)
+		m.hideExtensions = !m.hideExtensions
+		return m, nil
+
+	case "u":
+		// Unstage files here that don't match the current pattern
+		m.unstageNonMatching()
+		return m, nil
+
+	case "w":
+		// Expand/collapse the truncated row under the cursor
+		m.toggleExpandedRow()
+		return m, nil
+
+	case "i":
+		// Invert which printable files here are staged
+		m.invertStagedInDir()
+		return m, nil
+
+	case "s", "d":
+		// Keep/skip pass: s stages the file (never unstages), d skips it; both advance
+		if m.fileFocus == FocusFileList && m.fileCursor < len(m.files) {
+			file := m.files[m.fileCursor]
+			if msg.String() == "s" && file.IsPrintable && !m.markedFiles[file.Path] {
+				m.markedFiles[file.Path] = true
+				m.stageFile(file.Name, file.Path, m.currentDir, file.Size)
+			}
+			if m.fileCursor < len(m.files)-1 {
+				m.fileCursor++
+			} else {
+				m.setStatus(fmt.Sprintf("End of list, %d file(s) staged", len(m.stagedFiles)))
+			}
+		}
+		return m, nil
+
+	case "n":
+		// Create a folder here to organize files before printing
+		cmd := m.openMkdirPrompt()
+		return m, cmd
+
+	case "r":
+		// Rename the file under the cursor
+		cmd := m.openRenamePrompt()
+		return m, cmd
+
+	case "m":
+		// Toggle reviewing only what's marked
+		m.toggleMarkedOnly()
+		return m, nil
+
+	case ".":
+		m.toggleHidden()
+		return m, nil
+
+	case "o":
+		m.cycleSortMode()
+		return m, nil
+
+	case "O":
+		m.toggleSortOrder()
 		return m, nil
 
 	case " ":
@@ -1222,14 +1979,7 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					for _, f := range m.files {
 						if f.IsPrintable && !m.markedFiles[f.Path] {
 							m.markedFiles[f.Path] = true
-							m.stagedFiles = append(m.stagedFiles, StagedFile{
-								Name:       f.Name,
-								Path:       f.Path,
-								StagedFrom: m.currentDir,
-								Size:       f.Size,
-								AddedAt:    time.Now(),
-								Copies:     1,
-							})
+							m.stageFile(f.Name, f.Path, m.currentDir, f.Size)
 						}
 					}
 				}
@@ -1256,26 +2006,11 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						// Mark all printable files in directory
 						for _, entry := range entries {
 							if !entry.IsDir() {
-								ext := strings.ToLower(filepath.Ext(entry.Name()))
-								isPrintable := false
-								for _, pExt := range printableExts {
-									if ext == pExt {
-										isPrintable = true
-										break
-									}
-								}
-								if isPrintable {
+								if isPrintableName(entry.Name()) {
 									fullPath := filepath.Join(file.Path, entry.Name())
 									if !m.markedFiles[fullPath] {
 										m.markedFiles[fullPath] = true
-										m.stagedFiles = append(m.stagedFiles, StagedFile{
-											Name:       entry.Name(),
-											Path:       fullPath,
-											StagedFrom: file.Path,
-											Size:       entry.Size(),
-											AddedAt:    time.Now(),
-											Copies:     1,
-										})
+										m.stageFile(entry.Name(), fullPath, file.Path, entry.Size())
 									}
 								}
 							}
@@ -1285,24 +2020,11 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else if file.IsPrintable {
 				if m.markedFiles[file.Path] {
 					// Unmark and remove from staged
-					delete(m.markedFiles, file.Path)
-					for i := len(m.stagedFiles) - 1; i >= 0; i-- {
-						if m.stagedFiles[i].Path == file.Path {
-							m.stagedFiles = append(m.stagedFiles[:i], m.stagedFiles[i+1:]...)
-							break
-						}
-					}
+					m.unstagePath(file.Path)
 				} else {
 					// Mark and add to staged
 					m.markedFiles[file.Path] = true
-					m.stagedFiles = append(m.stagedFiles, StagedFile{
-						Name:       file.Name,
-						Path:       file.Path,
-						StagedFrom: m.currentDir,
-						Size:       file.Size,
-						AddedAt:    time.Now(),
-						Copies:     1,
-					})
+					m.stageFile(file.Name, file.Path, m.currentDir, file.Size)
 				}
 			}
 		}
@@ -1312,16 +2034,40 @@ func (m model) updateFilesPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m model) View() string {
+// View renders the UI. A panic in any renderer shows a fallback screen
+// instead of killing the program; the next resize or update redraws normally.
+func (m model) View() (view string) {
+	defer func() {
+		if r := recover(); r != nil {
+			view = renderPanicScreen(r, m.width)
+		}
+	}()
+	return m.view()
+}
+
+// renderPanicScreen is shown when rendering failed
+func renderPanicScreen(r interface{}, width int) string {
+	msg := fmt.Sprintf("Rendering failed: %v", r)
+	if width > 0 {
+		msg = lipgloss.NewStyle().Width(width).Render(msg)
+	}
+	return errorStyle.Render(msg) + "\n\n" +
+		dimStyle.Render("Try resizing the terminal • ctrl+c to quit")
+}
+
+func (m model) view() string {
 	if m.width == 0 || m.height == 0 {
 		return "Initializing..."
 	}
 
+	m.mouse.reset()
 	var mainView string
-	switch m.layoutMode {
-	case LayoutHorizontal:
+	switch {
+	case m.zoomed && m.layoutMode != LayoutSingle:
+		mainView = m.viewSinglePane()
+	case m.layoutMode == LayoutHorizontal:
 		mainView = m.viewSplitHorizontal()
-	case LayoutVertical:
+	case m.layoutMode == LayoutVertical:
 		mainView = m.viewSplitVertical()
 	default:
 		mainView = m.viewSinglePane()
@@ -1336,6 +2082,12 @@ func (m model) View() string {
 			overlay)
 	}
 
+	if m.overlay != OverlayNone {
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.renderOverlay())
+	}
+
 	return mainView
 }
 
@@ -1355,13 +2107,15 @@ func (m model) viewSplitHorizontal() string {
 	// Calculate pane dimensions
 	leftWidth := m.width / 2
 	rightWidth := m.width - leftWidth
-	paneHeight := m.height - helpHeight - pathHeight
+	paneHeight := m.height - helpHeight - pathHeight - m.extraHelpLines()
 
 	// Queue pane (left side)
 	queueBorder := inactiveBorderStyle
 	if m.activePane == PaneQueue {
 		queueBorder = activeBorderStyle
 	}
+	// Content starts inside the border and padding, below the path line
+	m.mouse.setOrigin(2, pathHeight+1)
 	queueContent := m.renderQueueContent(leftWidth-6, paneHeight-4)
 	queuePane := queueBorder.Copy().
 		Width(leftWidth - 2).
@@ -1373,6 +2127,7 @@ func (m model) viewSplitHorizontal() string {
 	if m.activePane == PaneFiles {
 		filesBorder = activeBorderStyle
 	}
+	m.mouse.setOrigin(leftWidth+2, pathHeight+1)
 	filesContent := m.renderFilesContent(rightWidth-6, paneHeight-4) // Account for border + padding
 	filesPane := filesBorder.Copy().
 		Width(rightWidth - 2).
@@ -1396,7 +2151,7 @@ func (m model) viewSplitVertical() string {
 	const pathHeight = 1 // Path display is 1 line
 
 	// Calculate pane heights
-	availableHeight := m.height - helpHeight - pathHeight
+	availableHeight := m.height - helpHeight - pathHeight - m.extraHelpLines()
 	topHeight := availableHeight / 2
 	bottomHeight := availableHeight - topHeight
 
@@ -1405,6 +2160,7 @@ func (m model) viewSplitVertical() string {
 	if m.activePane == PaneQueue {
 		queueBorder = activeBorderStyle
 	}
+	m.mouse.setOrigin(2, 1)
 	queueContent := m.renderQueueContent(m.width-6, topHeight-4)
 	queuePane := queueBorder.Copy().
 		Width(m.width - 2).
@@ -1419,6 +2175,7 @@ func (m model) viewSplitVertical() string {
 	if m.activePane == PaneFiles {
 		filesBorder = activeBorderStyle
 	}
+	m.mouse.setOrigin(2, topHeight+pathHeight+1)
 	filesContent := m.renderFilesContent(m.width-6, bottomHeight-4) // Account for border + padding
 	filesPane := filesBorder.Copy().
 		Width(m.width - 2).
@@ -1433,9 +2190,54 @@ func (m model) viewSplitVertical() string {
 }
 
 func (m *model) renderHelpBar() string {
+	// Safe mode and memory-only mode stay announced in front of the help and status messages
+	banner := m.helpBanners()
+	width := m.width - 2 - lipgloss.Width(banner)
+
+	if status := m.renderStatusLine(width); status != "" {
+		// Keep the height the panes were sized for
+		return banner + status + strings.Repeat("\n", m.extraHelpLines())
+	}
+
 	// Update help bar context and width
-	m.helpBar.Update(m.width - 2, m.activePane, m.layoutMode, m.fileFocus, m.queueSection)
-	return m.helpBar.Render()
+	m.helpBar.Update(width, m.activePane, m.layoutMode, m.fileFocus, m.queueSection)
+	if banner == "" {
+		return m.helpBar.Render()
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, banner, m.helpBar.Render())
+}
+
+// helpBanners returns the persistent markers shown in front of the help bar
+func (m model) helpBanners() string {
+	return m.safeModeBanner() + m.memoryOnlyBadge()
+}
+
+// memoryOnlyBadge marks that history or staging can't be saved to disk, so
+// nothing done this session will be remembered; "" when saving works
+func (m model) memoryOnlyBadge() string {
+	if m.tracker.IsPersistent() && !m.stagedSaveFailed {
+		return ""
+	}
+	return memoryOnlyBadgeStyle.Render("⚠ memory only ")
+}
+
+// safeModeBanner returns the safe mode marker shown in the help bar, or ""
+func (m model) safeModeBanner() string {
+	if dryRun {
+		return safeModeBannerStyle.Render(" DRY RUN — not printing ")
+	}
+	if !safeMode.Load() {
+		return ""
+	}
+	return safeModeBannerStyle.Render(" SAFE MODE — not printing ")
+}
+
+// extraHelpLines returns the lines the help bar takes beyond its usual one
+// when it wraps, so panes can shrink to make room
+func (m model) extraHelpLines() int {
+	width := m.width - 2 - lipgloss.Width(m.helpBanners())
+	m.helpBar.Update(width, m.activePane, m.layoutMode, m.fileFocus, m.queueSection)
+	return m.helpBar.Lines() - 1
 }
 
 func (m model) renderCurrentPath(width int) string {
@@ -1444,9 +2246,15 @@ func (m model) renderCurrentPath(width int) string {
 		displayDir = "~" + strings.TrimPrefix(displayDir, home)
 	}
 	
-	// Truncate if too long
-	if len(displayDir) > width-6 && width > 6 {
-		displayDir = "..." + displayDir[len(displayDir)-(width-9):]
+	// Leave room for the activity indicator when it's showing
+	pathWidth := width
+	if indicator := m.activityIndicator(); indicator != "" {
+		pathWidth -= lipgloss.Width(indicator) + 2
+	}
+
+	// Truncate if too long, keeping the current directory's name
+	if pathWidth > 9 {
+		displayDir = truncateLeft(displayDir, pathWidth-6)
 	}
 	
 	pathStyle := lipgloss.NewStyle().
@@ -1455,7 +2263,222 @@ func (m model) renderCurrentPath(width int) string {
 		Padding(0, 1). // Add padding instead of centering
 		Width(width)
 		
-	return pathStyle.Render("📂 " + displayDir)
+	return pathStyle.Render(m.withActivity("📂 " + displayDir))
+}
+
+// statusDuration is how long a status message replaces the help bar
+const statusDuration = 3 * time.Second
+
+// setStatus briefly shows a message in place of the help bar
+func (m *model) setStatus(text string) {
+	m.statusMsg = text
+	m.statusUntil = time.Now().Add(statusDuration)
+	m.statusIsErr = false
+}
+
+// setError briefly shows an error in place of the help bar
+func (m *model) setError(text string) {
+	m.setStatus(text)
+	m.statusIsErr = true
+}
+
+// reportOpenError shows why opening a file or folder failed, if it did
+func (m *model) reportOpenError(err error) {
+	if err != nil {
+		m.setError("Could not open: " + err.Error())
+	}
+}
+
+// setNotice shows an important warning for longer than a regular status
+func (m *model) setNotice(text string, d time.Duration) {
+	m.setError(text)
+	m.statusUntil = time.Now().Add(d)
+}
+
+// renderStatusLine renders the current status message, or "" once it has expired
+func (m model) renderStatusLine(width int) string {
+	if m.statusMsg == "" || time.Now().After(m.statusUntil) {
+		return ""
+	}
+	style := helpStyle
+	if m.statusIsErr {
+		style = errorStyle
+	}
+	return style.Copy().Width(width).MaxHeight(1).Render(m.statusMsg)
+}
+
+// stageFile adds a file to the staged list, applying any per-directory
+// defaults configured for the directory it was staged from
+func (m *model) stageFile(name, path, stagedFrom string, size int64) {
+	if config.BlockWritingFiles && m.isBeingWritten(path) {
+		// Half-written files print as garbage; stage them once they settle
+		delete(m.markedFiles, path)
+		m.setError(name + " is still being written, try again once it's complete")
+		return
+	}
+
+	file := StagedFile{
+		Name:       name,
+		Path:       path,
+		StagedFrom: stagedFrom,
+		Size:       size,
+		AddedAt:    time.Now(),
+		Copies:     1,
+		Duplex:     m.settings.Duplex,
+	}
+	if m.settings.Copies > 1 {
+		file.Copies = m.settings.Copies
+	}
+
+	// The printer's remembered options, then the directory's explicit ones
+	dir, defaults, ok := config.directoryDefaults(stagedFrom)
+	if ok && defaults.Printer != "" {
+		file.Printer = defaults.Printer
+	}
+	m.applyPrinterDefaults(&file)
+	if ok {
+		if defaults.Copies > 0 {
+			file.Copies = defaults.Copies
+		}
+		if defaults.Duplex != DuplexNone {
+			file.Duplex = defaults.Duplex
+		}
+		if defaults.Color != ColorAuto {
+			file.Color = defaults.Color
+		}
+		if defaults.Pages != "" {
+			file.Pages = defaults.Pages
+		}
+		if len(defaults.Extra) > 0 {
+			file.ExtraOptions = append([]string(nil), defaults.Extra...)
+		}
+		m.setStatus(fmt.Sprintf("Applied %s defaults: %s", dir, defaults.describe()))
+	}
+
+	m.stagedFiles = append(m.stagedFiles, file)
+}
+
+// isPrintableName reports whether a file name has a printable extension
+func isPrintableName(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, pExt := range config.PrintableExts {
+		if ext == pExt {
+			return true
+		}
+	}
+	return false
+}
+
+// printableFilesIn lists the printable files directly inside dir
+func printableFilesIn(dir string) []os.FileInfo {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []os.FileInfo
+	for _, entry := range entries {
+		if !entry.IsDir() && isPrintableName(entry.Name()) {
+			files = append(files, entry)
+		}
+	}
+	return files
+}
+
+// printDirectory submits every printable file in dir without staging them
+func (m *model) printDirectory(dir string) tea.Cmd {
+	_, defaults, _ := config.directoryDefaults(dir)
+
+	var cmds []tea.Cmd
+	for _, entry := range printableFilesIn(dir) {
+		if cmd := m.submitFile(filepath.Join(dir, entry.Name()), defaults); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	m.activePane = PaneQueue
+	m.queueSection = SectionActive
+	m.setStatus(fmt.Sprintf("Sent %d file(s) from %s", len(cmds), filepath.Base(dir)))
+	return tea.Batch(cmds...)
+}
+
+// submitFile queues a single file as a new print operation and submits it
+func (m *model) submitFile(path string, opts PrintOptions) tea.Cmd {
+	info, err := os.Stat(path)
+	if err != nil {
+		m.setError(fmt.Sprintf("Source file is gone: %s", path))
+		return nil
+	}
+	if opts.Copies < 1 {
+		opts.Copies = 1
+	}
+	opts.Printer = m.destination(opts.Printer)
+
+	opID := fmt.Sprintf("%s-%d", path, time.Now().UnixNano())
+	m.printOps = append(m.printOps, PrintOperation{
+		ID:        opID,
+		FilePath:  path,
+		FileName:  info.Name(),
+		Printer:   opts.Printer,
+		Copies:    opts.Copies,
+		Duplex:    opts.Duplex,
+		Color:     opts.Color,
+		Pages:     opts.Pages,
+		Extra:     opts.Extra,
+		Status:    StatusSending,
+		StartedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	return m.trackCmd(submitPrintJobCmd(opID, path, opts))
+}
+
+// trackCmd counts a dispatched command as in flight until its result message arrives
+func (m *model) trackCmd(cmd tea.Cmd) tea.Cmd {
+	m.inFlight++
+	return cmd
+}
+
+// untrackCmd marks one in-flight command as finished
+func (m *model) untrackCmd() {
+	if m.inFlight > 0 {
+		m.inFlight--
+	}
+}
+
+// activityIndicator returns a spinner label while background work is running
+func (m model) activityIndicator() string {
+	if m.inFlight == 0 {
+		return ""
+	}
+	sending := 0
+	for _, op := range m.printOps {
+		if op.Status == StatusSending {
+			sending++
+		}
+	}
+	if sending > 0 {
+		return fmt.Sprintf("%s sending %d…", m.spinner.View(), sending)
+	}
+	return m.spinner.View() + " refreshing…"
+}
+
+// withActivity appends the activity indicator to a title
+func (m model) withActivity(title string) string {
+	if indicator := m.activityIndicator(); indicator != "" {
+		return title + "  " + indicator
+	}
+	return title
+}
+
+// isTyping reports whether keystrokes are going to the pattern input
+func (m model) isTyping() bool {
+	return m.activePane == PaneFiles && m.fileFocus == FocusInput
+}
+
+// newScrollArea creates a ScrollableArea honoring the scrollbar toggle
+func (m *model) newScrollArea(width, height int) *ScrollableArea {
+	area := NewScrollableArea(width, height)
+	area.SetScrollbarEnabled(!m.hideScrollbar)
+	return area
 }
 
 func (m model) getRelativeStagedFiles() []StagedFile {
@@ -1463,41 +2486,347 @@ func (m model) getRelativeStagedFiles() []StagedFile {
 	return m.stagedFiles
 }
 
+// formatStagedFileName formats a staged row with the staged_format template.
+// The default shows the path relative to the current directory: just the
+// name for files in it, otherwise ../, ../../, subdirs/, etc.
 func (m model) formatStagedFileName(file StagedFile) string {
-	// Always show relative path from current directory
-	relPath, err := filepath.Rel(m.currentDir, file.Path)
-	if err != nil {
-		// If we can't get relative path, show full path
-		return file.Path
+	return m.formatStagedRow(config.StagedFormat, file)
+}
+
+// resetStagedPendingRemove resets PendingRemove for all staged files except the one at cursorIdx
+func (m *model) resetStagedPendingRemove(exceptIdx int) {
+	// The relative list mirrors m.stagedFiles; match by index since the same
+	// path can be staged more than once
+	for i := range m.stagedFiles {
+		if i != exceptIdx && m.stagedFiles[i].PendingRemove {
+			m.stagedFiles[i].PendingRemove = false
+			m.stagedFiles[i].Copies = 1
+		}
 	}
+}
+
+// completionGrace is how long a sent job that never showed up in the system
+// queue waits before counting as completed (track_completion)
+const completionGrace = 10 * time.Second
+
+// copiesDigitTimeout is how long typed digits are buffered into one number
+const copiesDigitTimeout = time.Second
 
-	// If file is in current directory, just show the name
-	if !strings.Contains(relPath, string(filepath.Separator)) && !strings.HasPrefix(relPath, "..") {
-		return relPath
+// setCopiesFromDigit sets the copies of the staged file at the cursor from typed digits.
+// Digits typed in quick succession form a multi-digit count; "0" alone resets to 1.
+func (m *model) setCopiesFromDigit(digit string) {
+	idx := m.stagedIndexAtCursor()
+	if idx < 0 {
+		return
+	}
+
+	if m.copiesDigits != "" && time.Since(m.copiesDigitsAt) < copiesDigitTimeout && len(m.copiesDigits) < 3 {
+		m.copiesDigits += digit
+	} else {
+		m.copiesDigits = digit
 	}
+	m.copiesDigitsAt = time.Now()
 
-	// Otherwise show the relative path (could be ../, ../../, subdirs/, etc.)
-	return relPath
+	copies, _ := strconv.Atoi(m.copiesDigits)
+	if copies < 1 {
+		copies = 1
+		m.copiesDigits = ""
+	}
+	m.stagedFiles[idx].Copies = copies
+	m.stagedFiles[idx].PendingRemove = false
 }
 
-// resetStagedPendingRemove resets PendingRemove for all staged files except the one at cursorIdx
-func (m *model) resetStagedPendingRemove(exceptIdx int) {
-	relativeStagedFiles := m.getRelativeStagedFiles()
-	for i, relFile := range relativeStagedFiles {
-		if i != exceptIdx {
-			for j := range m.stagedFiles {
-				if m.stagedFiles[j].Path == relFile.Path && m.stagedFiles[j].PendingRemove {
-					m.stagedFiles[j].PendingRemove = false
-					m.stagedFiles[j].Copies = 1
-				}
-			}
+// stagedIndexAtCursor returns the index into m.stagedFiles for the staged cursor, or -1.
+// The relative staged list mirrors m.stagedFiles, so the cursor maps directly.
+func (m model) stagedIndexAtCursor() int {
+	if m.stagedCursor < 0 || m.stagedCursor >= len(m.getRelativeStagedFiles()) {
+		return -1
+	}
+	return m.stagedCursor
+}
+
+// togglePinnedJob pins or unpins the system job under the active cursor
+func (m *model) togglePinnedJob() {
+	job, _ := m.activeItemAtCursor()
+	if job == nil {
+		m.setStatus("Only jobs already in the system queue can be pinned")
+		return
+	}
+
+	jobID := job.ID
+	if m.pinnedJobs[jobID] {
+		delete(m.pinnedJobs, jobID)
+	} else {
+		m.pinnedJobs[jobID] = true
+	}
+	m.sortPinnedJobs()
+
+	// Keep the cursor on the job that was toggled
+	for i, j := range m.jobs {
+		if j.ID == jobID {
+			m.activeCursor = i
+			break
+		}
+	}
+}
+
+// sortPinnedJobs moves pinned jobs to the front of m.jobs (keeping queue order otherwise)
+// and forgets pins for jobs that have left the queue
+func (m *model) sortPinnedJobs() {
+	present := make(map[string]bool, len(m.jobs))
+	for _, job := range m.jobs {
+		present[job.ID] = true
+	}
+	for id := range m.pinnedJobs {
+		if !present[id] {
+			delete(m.pinnedJobs, id)
+		}
+	}
+
+	sort.SliceStable(m.jobs, func(i, j int) bool {
+		return m.pinnedJobs[m.jobs[i].ID] && !m.pinnedJobs[m.jobs[j].ID]
+	})
+}
+
+// pinnedJobCount returns how many jobs at the front of m.jobs are pinned
+func (m model) pinnedJobCount() int {
+	count := 0
+	for _, job := range m.jobs {
+		if m.pinnedJobs[job.ID] {
+			count++
+		}
+	}
+	return count
+}
+
+// pinDividerLines returns the extra rendered lines used by the pinned-jobs divider
+func (m model) pinDividerLines(totalJobs int) int {
+	if m.problemsOnly {
+		return 0 // Only operations are listed, nothing is pinned
+	}
+	pinned := m.pinnedJobCount()
+	if pinned > 0 && pinned < totalJobs {
+		return 1
+	}
+	return 0
+}
+
+// problemOps returns the indices of failed and canceled operations, in order
+func (m model) problemOps() []int {
+	var indices []int
+	for i, op := range m.printOps {
+		if op.Status == StatusFailed || op.Status == StatusCanceled {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// jumpToProblem moves the active cursor to the next (step 1) or previous
+// (step -1) failed or canceled row, wrapping around the ends when
+// wrap_problem_navigation is set
+func (m *model) jumpToProblem(step int) {
+	count := m.getActualJobCount()
+	for n := 1; n < count || (n == count && config.WrapProblems); n++ {
+		index := m.activeCursor + step*n
+		if config.WrapProblems {
+			index = ((index % count) + count) % count
+		} else if index < 0 || index >= count {
+			break
+		}
+		if _, op := m.activeItemAt(index); op != nil && (op.Status == StatusFailed || op.Status == StatusCanceled) {
+			m.activeCursor = index
+			return
+		}
+	}
+	if config.WrapProblems || len(m.problemOps()) == 0 {
+		m.setStatus("No failed or canceled operations")
+	} else {
+		m.setStatus("No more failed or canceled operations this way")
+	}
+}
+
+// toggleProblemsOnly switches the active section between everything and only
+// failed/canceled operations
+func (m *model) toggleProblemsOnly() {
+	m.problemsOnly = !m.problemsOnly
+	m.activeCursor = 0
+	m.queueSection = SectionActive
+	if m.problemsOnly {
+		m.setStatus(fmt.Sprintf("Showing %d failed/canceled operation(s)", len(m.problemOps())))
+	}
+}
+
+// retryOperation resubmits the failed or canceled operation under the cursor
+// with its original options, replacing it in the list
+func (m *model) retryOperation() tea.Cmd {
+	_, op := m.activeItemAtCursor()
+	if op == nil || (op.Status != StatusFailed && op.Status != StatusCanceled) {
+		m.setStatus("Only failed or canceled operations can be retried")
+		return nil
+	}
+	retry := *op
+	for i := range m.printOps {
+		if m.printOps[i].ID == retry.ID {
+			m.printOps = append(m.printOps[:i], m.printOps[i+1:]...)
+			break
+		}
+	}
+	cmd := m.submitFile(retry.FilePath, PrintOptions{Printer: retry.Printer, Copies: retry.Copies, Duplex: retry.Duplex, Color: retry.Color, Pages: retry.Pages, Extra: retry.Extra})
+	m.clampCursors()
+	if cmd != nil {
+		m.setStatus("Retrying " + retry.FileName)
+	}
+	return cmd
+}
+
+// copyPrintCommand copies the lp command line that would print the staged
+// file under the cursor, or that submitted the operation under the cursor
+func (m *model) copyPrintCommand() {
+	var argv []string
+	if m.queueSection == SectionStaged {
+		idx := m.stagedIndexAtCursor()
+		if idx == -1 {
+			return
+		}
+		file := m.stagedFiles[idx]
+		argv = lpCommand(file.Path, PrintOptions{Printer: m.destination(file.Printer), Copies: file.Copies, Duplex: file.Duplex, Color: file.Color, Pages: file.Pages, Extra: file.ExtraOptions})
+	} else {
+		_, op := m.activeItemAtCursor()
+		if op == nil || op.FilePath == "" {
+			m.setStatus("Only files printed from here have a print command")
+			return
+		}
+		argv = lpCommand(op.FilePath, PrintOptions{Printer: op.Printer, Copies: op.Copies, Duplex: op.Duplex, Color: op.Color, Pages: op.Pages, Extra: op.Extra})
+	}
+
+	command := shellJoin(argv)
+	if err := copyToClipboard(command); err != nil {
+		m.setError("Could not copy print command: " + err.Error())
+		return
+	}
+	m.setStatus("Copied: " + command)
+}
+
+// removeOperation drops a finished operation from the list
+func (m *model) removeOperation(id string) {
+	for i := range m.printOps {
+		if m.printOps[i].ID == id {
+			m.printOps = append(m.printOps[:i], m.printOps[i+1:]...)
+			break
+		}
+	}
+	m.clampCursors()
+}
+
+// rememberQueueCursor saves the cursor of the current queue section
+func (m *model) rememberQueueCursor() {
+	if m.queueSection == SectionStaged {
+		m.queueCursorMemory[SectionStaged] = m.stagedCursor
+	} else {
+		m.queueCursorMemory[SectionActive] = m.activeCursor
+	}
+}
+
+// restoreQueueSection switches to a queue section and puts its cursor back
+// where it was last left, clamped to the current list
+func (m *model) restoreQueueSection(section QueueSection) {
+	if section != m.queueSection {
+		m.rememberQueueCursor()
+	}
+	m.queueSection = section
+	if saved, ok := m.queueCursorMemory[section]; ok {
+		if section == SectionStaged {
+			m.stagedCursor = saved
+		} else {
+			m.activeCursor = saved
+		}
+	}
+	m.clampCursors()
+}
+
+// focusStop is one place the focus cycle visits
+type focusStop int
+
+const (
+	StopActive focusStop = iota
+	StopStaged
+	StopInput
+	StopFileList
+	focusStopCount
+)
+
+// currentFocusStop returns where the focus is now
+func (m model) currentFocusStop() focusStop {
+	switch {
+	case m.activePane == PaneFiles && m.fileFocus == FocusInput:
+		return StopInput
+	case m.activePane == PaneFiles:
+		return StopFileList
+	case m.queueSection == SectionStaged:
+		return StopStaged
+	}
+	return StopActive
+}
+
+// cycleFocus moves the focus one stop forward (step 1) or back (step -1)
+// through active → staged → input → file list → active. The active section
+// is skipped in offline mode, where it isn't shown.
+func (m *model) cycleFocus(step int) tea.Cmd {
+	stop := m.currentFocusStop()
+	for {
+		stop = (stop + focusStop(step) + focusStopCount) % focusStopCount
+		if stop != StopActive || !config.Offline {
+			break
 		}
 	}
+
+	if m.activePane == PaneQueue {
+		m.rememberQueueCursor()
+	}
+	switch stop {
+	case StopActive, StopStaged:
+		section := SectionActive
+		if stop == StopStaged {
+			section = SectionStaged
+		}
+		m.activePane = PaneQueue
+		m.textInput.Blur()
+		m.restoreQueueSection(section)
+	case StopInput:
+		m.activePane = PaneFiles
+		m.fileFocus = FocusInput
+		return m.textInput.Focus()
+	case StopFileList:
+		m.activePane = PaneFiles
+		m.fileFocus = FocusFileList
+		m.textInput.Blur()
+	}
+	return nil
+}
+
+// clampCursors keeps every cursor within the bounds of its list so the
+// selection stays visible after the layout changes (e.g. on resize)
+func (m *model) clampCursors() {
+	clamp := func(cursor, count int) int {
+		if cursor >= count {
+			cursor = count - 1
+		}
+		if cursor < 0 {
+			cursor = 0
+		}
+		return cursor
+	}
+
+	m.activeCursor = clamp(m.activeCursor, m.getActualJobCount())
+	m.stagedCursor = clamp(m.stagedCursor, len(m.getRelativeStagedFiles()))
+	m.fileCursor = clamp(m.fileCursor, len(m.files))
 }
 
 func (m model) getDirectoryStatus(dirPath string) (totalPrintable int, stagedCount int, printingCount int) {
 	// Don't do file I/O! Use the existing state from the model
-	// Count files based on path prefix matching
+	// Count files based on path prefix matching, and the directory's cached
+	// listing when it was visited recently
 	
 	dirPrefix := dirPath + string(filepath.Separator)
 
@@ -1531,6 +2860,10 @@ func (m model) getDirectoryStatus(dirPath string) (totalPrintable int, stagedCou
 		}
 	}
 
+	if cached, ok := m.dirCache.printableCount(dirPath, m.showHidden); ok && cached > totalPrintable {
+		totalPrintable = cached
+	}
+
 	return totalPrintable, stagedCount, printingCount
 }
 
@@ -1542,52 +2875,60 @@ func max(a, b int) int {
 	return b
 }
 
-// findFilePathByJobID finds the FilePath for a system job by matching job ID against PrintOperations
+// findFilePathByJobID finds the FilePath for a system job by matching job ID against PrintOperations,
+// falling back to the tracker for jobs submitted in earlier sessions
 func (m model) findFilePathByJobID(jobID string) string {
 	for _, op := range m.printOps {
 		if op.SystemJobID == jobID {
 			return op.FilePath
 		}
 	}
+	if info, ok := m.tracker.Get(jobID); ok {
+		return info.FilePath
+	}
 	return ""
 }
 
+// trackJob records a successfully submitted operation in the tracker
+func (m *model) trackJob(op PrintOperation) {
+	err := m.tracker.AddJob(JobInfo{
+		JobID:       op.SystemJobID,
+		FilePath:    op.FilePath,
+		FileName:    op.FileName,
+		Copies:      op.Copies,
+		SubmittedAt: op.StartedAt,
+	})
+	if err != nil {
+		// Only reported once: the tracker is memory-only from now on
+		m.setNotice(err.Error(), 10*time.Second)
+	}
+}
+
 // getActualJobCount returns the deduplicated count of active jobs
 func (m model) getActualJobCount() int {
-	count := len(m.jobs)
-	// Add print operations that don't have corresponding system jobs (match by job ID)
-	for _, op := range m.printOps {
-		hasSystemJob := false
-		if op.SystemJobID != "" {
-			for _, job := range m.jobs {
-				if job.ID == op.SystemJobID {
-					hasSystemJob = true
-					break
-				}
-			}
-		}
-		// Count operations that are not in system queue and not successfully sent
-		if !hasSystemJob && op.Status != StatusSent {
-			count++
-		}
-	}
-	return count
+	return len(m.buildActiveJobList())
 }
 
 func (m model) getSelectionSymbol(file FileItem) string {
 	if file.IsDir {
-		_, staged, printing := m.getDirectoryStatus(file.Path)
-		// Simplified logic - we only show status if files are actually staged or printing
-		// We don't scan directories just to count printable files
+		total, staged, printing := m.getDirectoryStatus(file.Path)
+		// The total only covers unstaged files once the directory is cached;
+		// getDirectoryStatus doesn't scan directories itself
 		if printing > 0 && staged > 0 {
 			return "◑ " // Some printing, some staged
 		}
 		if printing > 0 {
 			return "● " // Has printing files
 		}
+		if staged > 0 && total > staged {
+			return "◐ " // Some staged, more printable
+		}
 		if staged > 0 {
 			return "◉ " // Has staged files
 		}
+		if total > 0 {
+			return "○ " // Printable files, none staged
+		}
 		return "  " // No special status
 	}
 	
@@ -1624,12 +2965,13 @@ func (m model) viewQueuePane() string {
 	title := titleStyle.Copy().
 		Width(contentWidth).
 		Align(lipgloss.Center).
-		Render("🖨  Printer Queue Manager")
+		Render(m.withActivity("🖨  Printer Queue Manager"))
 
 	// Content area
 	// Height available = m.height - 4 (for borders/padding)
 	// Height for content = available - 4 (title, 2 spacers, help)
-	contentHeight := m.height - 8
+	contentHeight := m.height - 8 - m.extraHelpLines()
+	m.mouse.setOrigin(0, 2)
 	queueContent := m.renderQueueContent(contentWidth, contentHeight)
 
 	// Help bar
@@ -1653,12 +2995,13 @@ func (m model) viewFilesPane() string {
 	title := titleStyle.Copy().
 		Width(contentWidth).
 		Align(lipgloss.Center).
-		Render("📁 Add Files to Print Queue")
+		Render(m.withActivity("📁 Add Files to Print Queue"))
 
 	// Files content
 	// Height available = m.height - 4 (for borders/padding)
 	// Height for content = available - 4 (title, 2 spacers, help)
-	contentHeight := m.height - 8
+	contentHeight := m.height - 8 - m.extraHelpLines()
+	m.mouse.setOrigin(0, 2)
 	filesContent := m.renderFilesContent(contentWidth, contentHeight)
 
 	// Help
@@ -1680,6 +3023,9 @@ func (m model) viewFilesPane() string {
 
 func formatSize(size int64) string {
 	const unit = 1024
+	if size < 0 {
+		return "—" // Unknown size
+	}
 	if size < unit {
 		return fmt.Sprintf("%d B", size)
 	}
@@ -1688,13 +3034,168 @@ func formatSize(size int64) string {
 		div *= unit
 		exp++
 	}
-	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+	return localizeDecimal(fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp]))
+}
+
+// truncate shortens s to fit in width terminal cells, ending in "..." when cut.
+// It never slices mid-rune and returns "" for widths below 1.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return strings.Repeat(".", width)
+	}
+
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width-3 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + "..."
+}
+
+// truncateLeft is truncate for paths: it cuts from the start instead, so
+// "/home/ana/long/dir" becomes ".../long/dir"
+func truncateLeft(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return strings.Repeat(".", width)
+	}
+
+	runes := []rune(s)
+	used, start := 0, len(runes)
+	for start > 0 {
+		w := lipgloss.Width(string(runes[start-1]))
+		if used+w > width-3 {
+			break
+		}
+		used += w
+		start--
+	}
+	return "..." + string(runes[start:])
+}
+
+// sanitizeForDisplay escapes control characters (newlines, tabs, terminal
+// escape sequences) that a file name may legally contain, so they are shown
+// as \n, \t, \x1b instead of breaking the layout or reaching the terminal
+func sanitizeForDisplay(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsControl) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			quoted := strconv.QuoteRune(r)
+			b.WriteString(quoted[1 : len(quoted)-1])
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// wrapText splits s into lines of at most width terminal cells, never mid-rune
+func wrapText(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+	var lines []string
+	var line strings.Builder
+	used := 0
+	for _, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width && used > 0 {
+			lines = append(lines, line.String())
+			line.Reset()
+			used = 0
+		}
+		line.WriteRune(r)
+		used += w
+	}
+	return append(lines, line.String())
+}
+
+// fitRow truncates a row's text to width, or wraps it onto continuation lines
+// when it is the expanded row
+func (m model) fitRow(rowID, text string, width int) (string, []string) {
+	text = sanitizeForDisplay(text)
+	if rowID == "" || rowID != m.expandedRow || width <= 0 || lipgloss.Width(text) <= width {
+		return truncate(text, width), nil
+	}
+	lines := wrapText(text, width)
+	return lines[0], lines[1:]
+}
+
+// continuationLines renders the wrapped remainder of an expanded row
+func continuationLines(prefix string, indent int, lines []string, style lipgloss.Style) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString("\n" + prefix + strings.Repeat(" ", indent) + style.Render(line))
+	}
+	return b.String()
+}
+
+// cursorRowID identifies the row under the cursor of the focused list
+func (m model) cursorRowID() string {
+	if m.activePane == PaneFiles {
+		if m.fileFocus == FocusFileList && m.fileCursor < len(m.files) {
+			return m.files[m.fileCursor].Path
+		}
+		return ""
+	}
+	if m.queueSection == SectionStaged {
+		if idx := m.stagedIndexAtCursor(); idx != -1 {
+			return "staged:" + m.stagedFiles[idx].Path
+		}
+		return ""
+	}
+	job, op := m.activeItemAtCursor()
+	if job != nil {
+		return "job:" + job.ID
+	}
+	if op != nil {
+		return "op:" + op.ID
+	}
+	return ""
+}
+
+// toggleExpandedRow expands the row under the cursor to show its full text;
+// moving the cursor away collapses it again
+func (m *model) toggleExpandedRow() {
+	id := m.cursorRowID()
+	if id == "" || id == m.expandedRow {
+		m.expandedRow = ""
+		return
+	}
+	m.expandedRow = id
 }
 
 func main() {
 	var versionFlag bool
 	flag.BoolVar(&versionFlag, "version", false, "Print version information")
 	flag.BoolVar(&versionFlag, "v", false, "Print version information")
+	gridFlag := flag.String("contact-grid", "4x5", "Contact sheet grid as COLSxROWS")
+	testPageFlag := flag.Bool("test-page", false, "Print a test page and exit")
+	offlineFlag := flag.Bool("offline", false, "Don't poll jobs or printers; only stage and submit")
+	flag.StringVar(&configFlag, "config", "", "Read settings from `path` instead of ~/.config/printer/config.toml (also $PRINTER_CONFIG)")
+	safeFlag := flag.Bool("safe", false, "Safe mode: go through the motions without printing anything")
+	dryRunFlag := flag.Bool("dry-run", false, "Show the lp command each print would run instead of running it")
+	reportFlag := flag.String("report", "", "Write a queue and history report to `file` (- for stdout) and exit")
+	printerFlag := flag.String("P", "", "Send staged files to `printer`; any unambiguous part of the name works")
 	flag.Parse()
 
 	if versionFlag {
@@ -1702,10 +3203,67 @@ func main() {
 		os.Exit(0)
 	}
 
+	cols, rows, err := parseGrid(*gridFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	contactSheetGrid.Cols, contactSheetGrid.Rows = cols, rows
+
+	config, configErr = loadConfig()
+	if *offlineFlag {
+		config.Offline = true
+	}
+	backend = selectBackend(config)
+	safeMode.Store(*safeFlag)
+	dryRun = *dryRunFlag
+
+	// Resolved first so --test-page goes to the chosen printer too
+	printer := ""
+	if *printerFlag != "" {
+		printer, err = matchPrinter(*printerFlag, backend.ListPrinters())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *testPageFlag {
+		if err := runTestPage(printer); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *reportFlag != "" {
+		if err := runReport(*reportFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	args := flag.Args()
 
-	p := tea.NewProgram(initialModel(args))
-	if _, err := p.Run(); err != nil {
+	m := initialModel(args)
+	if printer != "" {
+		m.selectPrinter(printer)
+	}
+
+	var opts []tea.ProgramOption
+	if config.Mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, opts...)
+	final, err := p.Run()
+	if fm, ok := final.(model); ok {
+		// Write staging edits made since the last periodic save
+		if err := fm.flushStagedState(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: staged files not saved: %v\n", err)
+		}
+	}
+	if err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newTestModel builds the app's model with its data and config directories in
+// a temporary directory, so tests never touch the user's files
+func newTestModel(t *testing.T) model {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Chdir(dir)
+
+	m := initialModel(nil)
+	m.overlay = OverlayNone
+	t.Cleanup(func() {
+		if m.dirWatcher != nil {
+			m.dirWatcher.Close()
+		}
+	})
+	return m
+}
+
+// pressKey sends a key through the model's update and returns the new model
+func pressKey(t *testing.T, m model, key string) (model, tea.Cmd) {
+	t.Helper()
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	updated, cmd := m.update(msg)
+	return updated.(model), cmd
+}
+
+func TestCancelJobSeenOnlyInLpstat(t *testing.T) {
+	fake := useFakeRunner(t)
+	m := newTestModel(t)
+	// A job submitted by another program: no operation, no source path
+	m.jobs = []PrintJob{{ID: "42", FileName: "other.pdf", Owner: "bob", Size: 10, Status: "active"}}
+	m.activePane = PaneQueue
+	m.queueSection = SectionActive
+	m.activeCursor = 0
+
+	m, cmd := pressKey(t, m, "x")
+	if cmd == nil {
+		t.Fatal("x on a system job returned no command")
+	}
+	if len(fake.calls) != 0 {
+		t.Fatalf("cancel ran inside Update: %q", fake.calls)
+	}
+
+	msg, ok := cmd().(jobCanceledMsg)
+	if !ok {
+		t.Fatalf("cancel command returned %T, want jobCanceledMsg", msg)
+	}
+	if len(fake.calls) != 1 || strings.Join(fake.calls[0], " ") != "cancel 42" {
+		t.Fatalf("ran %q, want cancel 42", fake.calls)
+	}
+
+	updated, _ := m.update(msg)
+	m = updated.(model)
+	if !strings.Contains(m.statusMsg, "Canceled job 42") {
+		t.Errorf("status = %q, want it to report the cancel", m.statusMsg)
+	}
+}
+
+func TestCancelJobFailureIsReported(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.err["cancel"] = errFake
+	m := newTestModel(t)
+
+	updated, _ := m.update(cancelJobCmd("42", "")())
+	m = updated.(model)
+	if !strings.Contains(m.errorMsg+m.statusMsg, "failed to cancel job 42") {
+		t.Errorf("error not reported: status %q, error %q", m.statusMsg, m.errorMsg)
+	}
+}
+
+func TestFailedRefreshKeepsSentJobs(t *testing.T) {
+	saved := config.TrackCompletion
+	config.TrackCompletion = true
+	t.Cleanup(func() { config.TrackCompletion = saved })
+
+	m := newTestModel(t)
+	m.jobs = []PrintJob{{ID: "7", FileName: "report.pdf"}}
+	m.printOps = []PrintOperation{{ID: "op1", FileName: "report.pdf", Status: StatusSent, SystemJobID: "7", SeenInQueue: true}}
+
+	updated, _ := m.update(jobsRefreshedMsg{err: errFake})
+	m = updated.(model)
+	if len(m.printOps) != 1 || m.printOps[0].Status != StatusSent {
+		t.Fatalf("failed refresh changed the sent job: %+v", m.printOps)
+	}
+	if len(m.jobs) != 1 {
+		t.Errorf("failed refresh dropped the known queue: %+v", m.jobs)
+	}
+
+	updated, _ = m.update(jobsRefreshedMsg{jobs: []PrintJob{}})
+	m = updated.(model)
+	if len(m.printOps) != 1 || m.printOps[0].Status != StatusCompleted {
+		t.Errorf("job that left a readable queue = %+v, want completed", m.printOps)
+	}
+}
+
+func TestResizeKeepsCursorVisible(t *testing.T) {
+	m := newTestModel(t)
+	m.files = nil
+	for i := 0; i < 60; i++ {
+		name := fmt.Sprintf("file-%02d.pdf", i)
+		m.files = append(m.files, FileItem{Name: name, Path: "/tmp/" + name, IsPrintable: true})
+	}
+	m.stagedFiles = []StagedFile{
+		{Name: "a.pdf", Path: "/tmp/a.pdf", Copies: 1},
+		{Name: "b.pdf", Path: "/tmp/b.pdf", Copies: 1},
+	}
+	m.fileCursor = 30
+	m.stagedCursor = 5 // Left over from a longer staged list
+	m.activePane = PaneFiles
+	m.fileFocus = FocusFileList
+
+	for _, size := range []tea.WindowSizeMsg{{Width: 200, Height: 80}, {Width: 60, Height: 20}, {Width: 10, Height: 4}} {
+		updated, _ := m.Update(size)
+		m = updated.(model)
+		view := m.View()
+		if m.fileCursor != 30 {
+			t.Errorf("%dx%d: file cursor moved to %d", size.Width, size.Height, m.fileCursor)
+		}
+		if m.stagedCursor != 1 {
+			t.Errorf("%dx%d: staged cursor = %d, want it clamped to 1", size.Width, size.Height, m.stagedCursor)
+		}
+		// Below 20 rows the file list has no room at all
+		if size.Height >= 20 && !strings.Contains(view, "file-30.pdf") {
+			t.Errorf("%dx%d: cursor row not visible:\n%s", size.Width, size.Height, view)
+		}
+	}
+}
+
+func TestViewAtNarrowWidths(t *testing.T) {
+	m := newTestModel(t)
+	m.currentDir = "/srv/共有/プロジェクト/quarterly reports (final)"
+	m.files = []FileItem{
+		{Name: "日本語のファイル名.pdf", Path: m.currentDir + "/日本語のファイル名.pdf", IsPrintable: true, Size: 2048},
+		{Name: "café crème (draft).pdf", Path: m.currentDir + "/café crème (draft).pdf", IsPrintable: true},
+	}
+	m.stagedFiles = []StagedFile{{Name: m.files[0].Name, Path: m.files[0].Path, Copies: 3}}
+	m.jobs = []PrintJob{{ID: "42", FileName: "ファイル with a very long name.pdf", Owner: "bob", Size: 10, Status: "active"}}
+
+	for _, width := range []int{1, 10, 20} {
+		for _, height := range []int{3, 24} {
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+			m = updated.(model)
+			for _, pane := range []ActivePane{PaneFiles, PaneQueue} {
+				m.activePane = pane
+				if view := m.View(); strings.Contains(view, "Rendering failed") {
+					t.Errorf("%dx%d pane %d: %s", width, height, pane, view)
+				}
+			}
+		}
+	}
+}
+
+func TestEmptyFlagStaysInsideThePane(t *testing.T) {
+	m := newTestModel(t)
+	m.currentDir = "/srv/scans"
+	name := "a rather long scanned document name that needs truncating.pdf"
+	m.files = []FileItem{{Name: name, Path: m.currentDir + "/" + name, IsPrintable: true}}
+	m.stagedFiles = []StagedFile{{Name: name, Path: m.files[0].Path, Copies: 1}}
+
+	const width = 40
+	for pane, content := range map[string]string{
+		"files":  m.renderFilesContent(width, 10),
+		"staged": m.renderQueueContent(width, 10),
+	} {
+		if !strings.Contains(content, emptySuffix) {
+			t.Errorf("%s: empty file not flagged:\n%s", pane, content)
+		}
+		for _, line := range strings.Split(content, "\n") {
+			if strings.Contains(line, "scanned") && lipgloss.Width(line) > width {
+				t.Errorf("%s: row %q is %d cells wide, pane is %d", pane, line, lipgloss.Width(line), width)
+			}
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"report.pdf", 20, "report.pdf"},
+		{"quarterly report (final).pdf", 20, "quarterly report ..."},
+		{"quarterly report (final).pdf", 10, "quarter..."},
+		{"quarterly report (final).pdf", 1, "."},
+		{"café crème brûlée.pdf", 10, "café cr..."},
+		{"日本語のファイル名.pdf", 10, "日本語..."}, // Wide runes take two cells
+		{"日本語のファイル名.pdf", 1, "."},
+		{"anything", 0, ""},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if w := lipgloss.Width(got); w > tt.width {
+			t.Errorf("truncate(%q, %d) is %d cells wide", tt.s, tt.width, w)
+		}
+	}
+}
+
+func TestTruncateLeft(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"~/docs", 20, "~/docs"},
+		{"~/projects/printer/testdata", 20, ".../printer/testdata"},
+		{"~/projects/printer/testdata", 10, "...estdata"},
+		{"~/projects/printer/testdata", 1, "."},
+		{"~/写真/日本語のフォルダ", 10, "...ォルダ"},
+		{"~/café/crème", 10, "...é/crème"},
+	}
+	for _, tt := range tests {
+		got := truncateLeft(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateLeft(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if w := lipgloss.Width(got); w > tt.width {
+			t.Errorf("truncateLeft(%q, %d) is %d cells wide", tt.s, tt.width, w)
+		}
+	}
+}
+
+func TestSanitizeForDisplay(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"my report (final).pdf", "my report (final).pdf"},
+		{"café 日本語.pdf", "café 日本語.pdf"},
+		{"two\nlines.pdf", `two\nlines.pdf`},
+		{"tab\there.pdf", `tab\there.pdf`},
+		{"\x1b[31mred.pdf", `\x1b[31mred.pdf`},
+		{"del\x7f.pdf", `del\x7f.pdf`},
+	}
+	for _, tt := range tests {
+		if got := sanitizeForDisplay(tt.in); got != tt.want {
+			t.Errorf("sanitizeForDisplay(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatStagedFileName(t *testing.T) {
+	m := newTestModel(t)
+	m.currentDir = "/home/ana/docs"
+
+	tests := []struct {
+		path, want string
+	}{
+		{"/home/ana/docs/my report (final).pdf", "my report (final).pdf"},
+		{"/home/ana/docs/cafés/日本語.pdf", "cafés/日本語.pdf"},
+		{"/home/ana/other dir/a b.pdf", "../other dir/a b.pdf"},
+	}
+	for _, tt := range tests {
+		file := StagedFile{Name: filepath.Base(tt.path), Path: tt.path, Copies: 1}
+		if got := m.formatStagedFileName(file); got != tt.want {
+			t.Errorf("formatStagedFileName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	// Control characters are escaped when the row is fitted for display
+	file := StagedFile{Name: "a\nb.pdf", Path: "/home/ana/docs/a\nb.pdf", Copies: 1}
+	if got, _ := m.fitRow("", m.formatStagedFileName(file), 40); got != `a\nb.pdf` {
+		t.Errorf("staged row with a newline = %q, want %q", got, `a\nb.pdf`)
+	}
+}
+
+func TestPollingDoesNotShowActivity(t *testing.T) {
+	useFakeRunner(t)
+	m := newTestModel(t)
+	m.inFlight = 0
+
+	updated, cmd := m.update(tickMsg(time.Now()))
+	m = updated.(model)
+	if indicator := m.activityIndicator(); indicator != "" {
+		t.Errorf("tick showed %q while polling", indicator)
+	}
+	if cmd == nil {
+		t.Fatal("tick returned no command")
+	}
+	updated, _ = m.update(jobsRefreshedMsg{polled: true})
+	m = updated.(model)
+	if m.inFlight != 0 {
+		t.Errorf("polled refresh left inFlight = %d", m.inFlight)
+	}
+
+	// A refresh asked for with r is shown until it comes back
+	m.activePane = PaneQueue
+	m, _ = pressKey(t, m, "r")
+	if m.activityIndicator() == "" {
+		t.Error("r showed no activity")
+	}
+	updated, _ = m.update(jobsRefreshedMsg{})
+	m = updated.(model)
+	if indicator := m.activityIndicator(); indicator != "" {
+		t.Errorf("activity %q still shown after the refresh", indicator)
+	}
+}
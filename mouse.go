@@ -0,0 +1,140 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mouseTarget is a list that reacts to clicks and the wheel
+type mouseTarget int
+
+const (
+	TargetActive mouseTarget = iota
+	TargetStaged
+	TargetFiles
+)
+
+// mouseToggleColumns is how far from a file row's left edge a click hits the
+// ◉/○ symbol and toggles staging instead of only moving the cursor
+const mouseToggleColumns = 5
+
+// mouseRegion is where a list's scroll area was last drawn on screen
+type mouseRegion struct {
+	target        mouseTarget
+	x, y          int
+	width, height int
+	offset        int   // First content line shown
+	rows          []int // Item index of each content line, -1 for dividers
+}
+
+// mouseLayout maps screen cells back to list rows. The views record it while
+// rendering; the model holds it by pointer so the record outlives View's copy.
+type mouseLayout struct {
+	originX, originY int // Screen position of the content being rendered
+	regions          []mouseRegion
+}
+
+// reset forgets the regions of the previous frame
+func (l *mouseLayout) reset() {
+	l.regions = l.regions[:0]
+}
+
+// setOrigin sets where the next pane's content starts on screen
+func (l *mouseLayout) setOrigin(x, y int) {
+	l.originX, l.originY = x, y
+}
+
+// add records a scroll area drawn at line of the current pane's content
+func (l *mouseLayout) add(target mouseTarget, line, width, height int, area *ScrollableArea, rows []int) {
+	current, _, _ := area.GetScrollPosition()
+	l.regions = append(l.regions, mouseRegion{
+		target: target,
+		x:      l.originX,
+		y:      l.originY + line,
+		width:  width,
+		height: height,
+		offset: current - 1,
+		rows:   rows,
+	})
+}
+
+// at returns the region under a screen cell and the item on that line (-1
+// when the line holds no item)
+func (l *mouseLayout) at(x, y int) (mouseRegion, int, bool) {
+	for _, r := range l.regions {
+		if x < r.x || x >= r.x+r.width || y < r.y || y >= r.y+r.height {
+			continue
+		}
+		line := r.offset + y - r.y
+		if line < 0 || line >= len(r.rows) {
+			return r, -1, true
+		}
+		return r, r.rows[line], true
+	}
+	return mouseRegion{}, -1, false
+}
+
+// handleMouse moves the cursor to a clicked row, toggles staging when a
+// file's ◉/○ symbol is clicked, and moves the list under the pointer with
+// the wheel
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.overlay != OverlayNone || m.helpBar.IsShowingFullHelp() {
+		return m, nil
+	}
+	region, item, ok := m.mouse.at(msg.X, msg.Y)
+	if !ok || msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.wheelScroll(region.target, -1)
+	case tea.MouseButtonWheelDown:
+		m.wheelScroll(region.target, 1)
+	case tea.MouseButtonLeft:
+		if item < 0 {
+			return m, nil
+		}
+		if region.target == TargetFiles {
+			if m.activePane == PaneQueue {
+				m.rememberQueueCursor()
+			}
+			m.activePane = PaneFiles
+			m.fileFocus = FocusFileList
+			m.textInput.Blur()
+			m.fileCursor = item
+			if msg.X-region.x < mouseToggleColumns {
+				// Same as pressing space on the row
+				return m.update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+			}
+			return m, nil
+		}
+		section := SectionActive
+		if region.target == TargetStaged {
+			section = SectionStaged
+		}
+		m.activePane = PaneQueue
+		m.textInput.Blur()
+		m.restoreQueueSection(section)
+		if section == SectionStaged {
+			m.stagedCursor = item
+		} else {
+			m.activeCursor = item
+		}
+		m.clampCursors()
+	}
+	return m, nil
+}
+
+// wheelScroll moves the cursor of the list under the pointer by step rows,
+// scrolling it along, without moving the focus
+func (m *model) wheelScroll(target mouseTarget, step int) {
+	switch target {
+	case TargetActive:
+		m.activeCursor += step
+	case TargetStaged:
+		m.stagedCursor += step
+	case TargetFiles:
+		m.fileCursor += step
+	}
+	m.clampCursors()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// onboardedMarker is created in the data dir once the intro has been dismissed
+const onboardedMarker = ".onboarded"
+
+// needsOnboarding reports whether this is the first launch
+func needsOnboarding() bool {
+	dir, err := dataDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, onboardedMarker))
+	return os.IsNotExist(err)
+}
+
+// markOnboarded records that the intro was seen; failures just mean it shows again
+func markOnboarded() {
+	dir, err := dataDir()
+	if err != nil || os.MkdirAll(dir, 0755) != nil {
+		return
+	}
+	os.WriteFile(filepath.Join(dir, onboardedMarker), nil, 0644)
+}
+
+// updateOnboarding dismisses the intro on any key
+func (m model) updateOnboarding(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.overlay = OverlayNone
+	markOnboarded()
+	return m, nil
+}
+
+// renderOnboarding explains the stage-then-print flow
+func (m model) renderOnboarding() string {
+	steps := []struct{ key, text string }{
+		{"a / f", "browse files (tab switches panes)"},
+		{"space", "mark a file to stage it"},
+		{"P", "print everything staged"},
+		{"X", "clear the staged list"},
+		{"?", "all shortcuts"},
+	}
+
+	var content strings.Builder
+	content.WriteString(helpWindowTitleStyle.Render("Welcome to printer"))
+	content.WriteString("\n\n")
+	content.WriteString(overlayValueStyle.Render("Files are staged first, then printed together."))
+	content.WriteString("\n\n")
+	for _, step := range steps {
+		content.WriteString(overlayLabelStyle.Render(step.key))
+		content.WriteString(overlayValueStyle.Render(step.text))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(helpActionStyle.Render("press any key to start"))
+	return helpWindowStyle.Render(content.String())
+}
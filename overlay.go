@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// OverlayKind identifies which floating window is shown over the main view
+type OverlayKind int
+
+const (
+	OverlayNone OverlayKind = iota
+	OverlayJobDetail
+	OverlayPrinterPicker
+	OverlayConfirm
+	OverlayPrompt
+	OverlayOnboarding
+	OverlayPrinterState
+	OverlayPrinterQueues
+	OverlayHistory
+)
+
+// ConfirmAction is the action a confirmation overlay runs on "y"
+type ConfirmAction int
+
+const (
+	ConfirmPrintDirectory ConfirmAction = iota // Print every printable file in confirmDir
+	ConfirmPrintStaged                         // Print staged files despite recent duplicates
+	ConfirmQuit                                // Quit although staged files won't be kept
+	ConfirmDuplicateBatch                      // Stage the batch again for confirmPrinter
+)
+
+var (
+	overlayLabelStyle = lipgloss.NewStyle().
+				Foreground(theme.Overlay1).
+				Width(12)
+
+	overlayValueStyle = lipgloss.NewStyle().
+				Foreground(theme.Text)
+)
+
+// activeItemAtCursor resolves the active-section row under the cursor, in render order:
+// system jobs first, then print operations without a matching system job.
+// Either return value may be nil.
+func (m model) activeItemAtCursor() (*PrintJob, *PrintOperation) {
+	return m.activeItemAt(m.activeCursor)
+}
+
+// activeItemAt resolves the active-section row at index, like activeItemAtCursor
+func (m model) activeItemAt(index int) (*PrintJob, *PrintOperation) {
+	entries := m.buildActiveJobList()
+	if index < 0 || index >= len(entries) {
+		return nil, nil
+	}
+	return entries[index].Job, entries[index].Op
+}
+
+// openJobDetail opens the detail overlay for the active row under the cursor
+func (m *model) openJobDetail() {
+	job, op := m.activeItemAtCursor()
+	if job == nil && op == nil {
+		return
+	}
+	m.detailJobID = ""
+	m.detailOpID = ""
+	if job != nil {
+		m.detailJobID = job.ID
+	}
+	if op != nil {
+		m.detailOpID = op.ID
+	}
+	m.overlay = OverlayJobDetail
+}
+
+// updateOverlay handles keys while an overlay is open; all keys are consumed
+func (m model) updateOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		if m.overlay == OverlayConfirm && m.confirmAction == ConfirmQuit {
+			// A second ctrl+c confirms losing the staged files
+			return m, tea.Quit
+		}
+		// Quit like from the main view, warning about unsaved staged files
+		m.overlay = OverlayNone
+		cmd := m.requestQuit()
+		return m, cmd
+	}
+	if m.overlay == OverlayPrinterPicker {
+		return m.updatePrinterPicker(msg)
+	}
+	if m.overlay == OverlayConfirm {
+		return m.updateConfirm(msg)
+	}
+	if m.overlay == OverlayPrompt {
+		return m.updatePrompt(msg)
+	}
+	if m.overlay == OverlayOnboarding {
+		return m.updateOnboarding(msg)
+	}
+	if m.overlay == OverlayHistory {
+		return m.updateHistory(msg)
+	}
+
+	switch msg.String() {
+	case "esc", "q", "enter", "i":
+		m.overlay = OverlayNone
+	case "r":
+		if m.overlay == OverlayPrinterState {
+			m.printerStateLoading = true
+			return m, printerStateCmd(m.printerState.Name)
+		}
+	case "R":
+		if m.overlay == OverlayJobDetail {
+			return m, m.openReprintPicker()
+		}
+	}
+	return m, nil
+}
+
+// renderOverlay renders the current overlay window
+func (m model) renderOverlay() string {
+	switch m.overlay {
+	case OverlayJobDetail:
+		return m.renderJobDetail()
+	case OverlayPrinterPicker:
+		return m.renderPrinterPicker()
+	case OverlayConfirm:
+		return m.renderConfirm()
+	case OverlayPrompt:
+		return m.renderPrompt()
+	case OverlayOnboarding:
+		return m.renderOnboarding()
+	case OverlayPrinterState:
+		return m.renderPrinterState()
+	case OverlayPrinterQueues:
+		return m.renderPrinterQueues()
+	case OverlayHistory:
+		return m.renderHistory()
+	}
+	return ""
+}
+
+// openConfirm asks a yes/no question before running action
+func (m *model) openConfirm(action ConfirmAction, question string) {
+	m.confirmAction = action
+	m.confirmText = question
+	m.overlay = OverlayConfirm
+}
+
+// updateConfirm handles y/n while a confirmation is open
+func (m model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		m.overlay = OverlayNone
+		return m, m.runConfirmed()
+	case "n", "N", "esc", "q":
+		m.overlay = OverlayNone
+	case "d":
+		if m.confirmAction == ConfirmPrintStaged {
+			// Drop the recently printed files and print the rest
+			m.overlay = OverlayNone
+			removed := m.deselectRecentlyPrinted()
+			m.setStatus(fmt.Sprintf("Deselected %d recently printed file(s)", removed))
+			cmd := m.printStaged()
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+// runConfirmed performs the action that was confirmed
+func (m *model) runConfirmed() tea.Cmd {
+	switch m.confirmAction {
+	case ConfirmPrintDirectory:
+		return m.printDirectory(m.confirmDir)
+	case ConfirmPrintStaged:
+		return m.printStaged()
+	case ConfirmQuit:
+		return tea.Quit
+	case ConfirmDuplicateBatch:
+		m.duplicateBatchTo(m.confirmPrinter)
+	}
+	return nil
+}
+
+// renderConfirm renders the yes/no question
+func (m model) renderConfirm() string {
+	var content strings.Builder
+	content.WriteString(helpWindowTitleStyle.Render("Confirm"))
+	content.WriteString("\n\n")
+	content.WriteString(overlayValueStyle.Render(m.confirmText))
+	content.WriteString("\n\n")
+	content.WriteString(helpActionStyle.Render("y: yes • n: no"))
+	return helpWindowStyle.Render(content.String())
+}
+
+// detailJob returns the system job and/or operation shown in the detail overlay
+func (m model) detailJob() (*PrintJob, *PrintOperation) {
+	var job *PrintJob
+	for i := range m.jobs {
+		if m.detailJobID != "" && m.jobs[i].ID == m.detailJobID {
+			job = &m.jobs[i]
+			break
+		}
+	}
+	var op *PrintOperation
+	for i := range m.printOps {
+		if (m.detailOpID != "" && m.printOps[i].ID == m.detailOpID) ||
+			(m.detailJobID != "" && m.printOps[i].SystemJobID == m.detailJobID) {
+			op = &m.printOps[i]
+			break
+		}
+	}
+	return job, op
+}
+
+// detailSource returns the source file of the detail overlay's job, if known
+func (m model) detailSource() (path, name string, copies int) {
+	job, op := m.detailJob()
+	if op != nil && op.FilePath != "" {
+		return op.FilePath, op.FileName, op.Copies
+	}
+	if info, ok := m.tracker.Get(m.detailJobID); ok {
+		return info.FilePath, info.FileName, info.Copies
+	}
+	if job != nil {
+		return "", job.FileName, 1
+	}
+	return "", "", 1
+}
+
+// renderJobDetail shows everything known about a job, merged from lpq and our PrintOperation
+func (m model) renderJobDetail() string {
+	job, op := m.detailJob()
+
+	var content strings.Builder
+	content.WriteString(helpWindowTitleStyle.Render("Job Details"))
+	content.WriteString("\n\n")
+
+	row := func(label, value string) {
+		if value == "" {
+			return
+		}
+		content.WriteString(overlayLabelStyle.Render(label))
+		content.WriteString(overlayValueStyle.Render(sanitizeForDisplay(value)))
+		content.WriteString("\n")
+	}
+
+	if job == nil && op == nil {
+		content.WriteString(dimStyle.Render("Job is no longer in the queue"))
+		content.WriteString("\n")
+	}
+
+	if job != nil {
+		row("Job ID", job.ID)
+		row("File", job.FileName)
+		row("Owner", job.Owner)
+		row("Size", formatSize(job.Size))
+		row("Status", job.Status)
+		if info, ok := m.tracker.Get(job.ID); ok && op == nil {
+			row("Path", info.FilePath)
+			row("Submitted", fmt.Sprintf("%s (%s)", info.SubmittedAt.Format("Jan 2 15:04"), m.formatTimeAgo(info.SubmittedAt)))
+		}
+	}
+	if op != nil {
+		if job == nil {
+			row("Job ID", op.SystemJobID)
+			row("File", op.FileName)
+		}
+		row("Operation", op.statusLabel())
+		row("Printer", op.Printer)
+		row("Path", op.FilePath)
+		row("Command", op.Command)
+		row("Submitted", fmt.Sprintf("%s (%s)", op.StartedAt.Format("15:04:05"), m.formatTimeAgo(op.StartedAt)))
+		if op.Error != nil {
+			row("Error", op.Error.Error())
+		}
+	}
+
+	content.WriteString("\n")
+	var actions []string
+	if job != nil || (op != nil && (op.Status == StatusSending || op.Status == StatusPending)) {
+		actions = append(actions, "x: cancel")
+	} else if op != nil {
+		actions = append(actions, "x: remove")
+	}
+	if op != nil && op.FilePath != "" {
+		actions = append(actions, "o: open file", "O: open folder")
+	}
+	if path, _, _ := m.detailSource(); path != "" {
+		actions = append(actions, "R: reprint to…")
+	}
+	if len(actions) > 0 {
+		content.WriteString(helpActionStyle.Render("Actions: " + strings.Join(actions, " • ")))
+		content.WriteString("\n")
+	}
+	content.WriteString(helpActionStyle.Render("Press esc to close"))
+
+	return helpWindowStyle.Render(content.String())
+}
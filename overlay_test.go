@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCtrlCInOverlayWarnsAboutStagedFiles(t *testing.T) {
+	saved := config.PersistStaging
+	config.PersistStaging = false
+	t.Cleanup(func() { config.PersistStaging = saved })
+
+	ctrlC := tea.KeyMsg{Type: tea.KeyCtrlC}
+	for _, overlay := range []OverlayKind{OverlayJobDetail, OverlayPrinterPicker, OverlayPrompt,
+		OverlayOnboarding, OverlayHistory, OverlayPrinterQueues} {
+		m := newTestModel(t)
+		m.stagedFiles = []StagedFile{{Name: "a.pdf", Path: "/tmp/a.pdf", Copies: 1}}
+		m.overlay = overlay
+
+		updated, cmd := m.update(ctrlC)
+		m = updated.(model)
+		if cmd != nil || m.overlay != OverlayConfirm || m.confirmAction != ConfirmQuit {
+			t.Errorf("overlay %d: ctrl+c quit without asking (overlay %d)", overlay, m.overlay)
+			continue
+		}
+
+		_, cmd = m.update(ctrlC)
+		if cmd == nil {
+			t.Errorf("overlay %d: second ctrl+c didn't quit", overlay)
+		} else if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Errorf("overlay %d: second ctrl+c returned %T, want tea.QuitMsg", overlay, cmd())
+		}
+	}
+}
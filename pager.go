@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pagerExitedMsg is sent when the pager started with V exits
+type pagerExitedMsg struct {
+	err error
+}
+
+// isTextFile sniffs the start of a file to tell text from binary content
+func isTextFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := f.Read(head)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	if n == 0 {
+		// Nothing to sniff; an empty file is harmless to page
+		return true, nil
+	}
+	return strings.HasPrefix(http.DetectContentType(head[:n]), "text/"), nil
+}
+
+// pagerCmd suspends the TUI and shows path in $PAGER (or less), resuming the
+// app when the pager exits
+func pagerCmd(path string) tea.Cmd {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	cmd := exec.Command("sh", "-c", pager+` "$1"`, "pager", path)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return pagerExitedMsg{err: err}
+	})
+}
+
+// sourcePathAtCursor returns the file behind the queue row under the cursor,
+// "" when it isn't known (e.g. jobs submitted outside this app)
+func (m model) sourcePathAtCursor() string {
+	if m.queueSection == SectionStaged {
+		if idx := m.stagedIndexAtCursor(); idx != -1 {
+			return m.stagedFiles[idx].Path
+		}
+		return ""
+	}
+	job, op := m.activeItemAtCursor()
+	if op != nil {
+		return op.FilePath
+	}
+	if job != nil {
+		return m.findFilePathByJobID(job.ID)
+	}
+	return ""
+}
+
+// viewInPager pages the text file under the cursor without leaving the terminal
+func (m *model) viewInPager() tea.Cmd {
+	path := m.sourcePathAtCursor()
+	if path == "" {
+		m.setStatus("No source file known for this row")
+		return nil
+	}
+	text, err := isTextFile(path)
+	if err != nil {
+		m.setError(fmt.Sprintf("Cannot read %s: %v", filepath.Base(path), err))
+		return nil
+	}
+	if !text {
+		m.setStatus(filepath.Base(path) + " is not a text file; o opens it instead")
+		return nil
+	}
+	return pagerCmd(path)
+}
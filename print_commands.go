@@ -1,13 +1,12 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,16 +21,42 @@ const (
 	StatusSent     PrintStatus = "sent"
 	StatusFailed   PrintStatus = "failed"
 	StatusCanceled PrintStatus = "canceled"
+	// StatusCompleted: a sent job that has since left the system queue,
+	// i.e. finished printing (only with track_completion)
+	StatusCompleted PrintStatus = "completed"
+	// StatusSafeMode: submitted while safe mode was on, so nothing was printed
+	StatusSafeMode PrintStatus = "safe_mode"
 )
 
+// safeMode turns every submission into a no-op (--safe, D). Read from the
+// command goroutines, hence atomic.
+var safeMode atomic.Bool
+
+// dryRun (--dry-run) records the lp command each submission would run on its
+// operation instead of running it. Set once before the UI starts.
+var dryRun bool
+
 // PrintStatusMsg is sent when a print job status changes
 type PrintStatusMsg struct {
 	FileID      string
 	Status      PrintStatus
 	SystemJobID string // CUPS job ID (e.g., "216") for matching with lpq
+	Command     string // The lp command that would have run, in dry-run mode
 	Error       error
 }
 
+// clearSentMsg asks to drop a sent operation once config.ClearSentAfter passes
+type clearSentMsg struct {
+	OpID string
+}
+
+// clearSentCmd schedules the removal of a sent operation
+func clearSentCmd(opID string) tea.Cmd {
+	return tea.Tick(config.ClearSentAfter, func(time.Time) tea.Msg {
+		return clearSentMsg{OpID: opID}
+	})
+}
+
 // parseJobIDFromLpOutput extracts job number from lp output
 // Input: "request id is EPSON_ET_2810_Series-216 (1 file(s))"
 // Output: "216"
@@ -60,8 +85,182 @@ func parseJobIDFromLpOutput(output string) string {
 	return fullID
 }
 
+// DuplexMode selects single- or double-sided output
+type DuplexMode int
+
+const (
+	DuplexNone  DuplexMode = iota // One-sided (the default)
+	DuplexLong                    // Two-sided, flipped on the long edge (portrait books)
+	DuplexShort                   // Two-sided, flipped on the short edge (landscape pads)
+)
+
+// sidesOption returns the CUPS sides= option for the mode, "" for one-sided
+func (d DuplexMode) sidesOption() string {
+	switch d {
+	case DuplexLong:
+		return "sides=two-sided-long-edge"
+	case DuplexShort:
+		return "sides=two-sided-short-edge"
+	}
+	return ""
+}
+
+// String names the mode for status messages
+func (d DuplexMode) String() string {
+	switch d {
+	case DuplexLong:
+		return "two-sided (long edge)"
+	case DuplexShort:
+		return "two-sided (short edge)"
+	}
+	return "one-sided"
+}
+
+// ColorMode forces color or grayscale output; ColorAuto leaves it to the printer
+type ColorMode int
+
+const (
+	ColorAuto ColorMode = iota // The printer's default (no option sent)
+	ColorColor
+	ColorGray
+)
+
+// colorOption returns the CUPS ColorModel option for the mode, "" for auto
+func (c ColorMode) colorOption() string {
+	switch c {
+	case ColorColor:
+		return "ColorModel=RGB"
+	case ColorGray:
+		return "ColorModel=Gray"
+	}
+	return ""
+}
+
+// String names the mode for status messages
+func (c ColorMode) String() string {
+	switch c {
+	case ColorColor:
+		return "color"
+	case ColorGray:
+		return "grayscale"
+	}
+	return "printer default color"
+}
+
+// PrintOptions are the per-job settings passed to lp
+type PrintOptions struct {
+	Printer string // Destination queue, "" for the system default
+	Copies  int
+	Duplex  DuplexMode
+	Color   ColorMode
+	Pages   string   // page-ranges value, e.g. "2-5,8"; "" prints every page
+	Extra   []string // Additional key=value options, passed verbatim as -o
+}
+
+// cupsOptions returns the -o options for the job: duplex, color and page
+// range, then Extra. Each key appears once; the fields win over Extra.
+func (o PrintOptions) cupsOptions() []string {
+	var options []string
+	if sides := o.Duplex.sidesOption(); sides != "" {
+		options = append(options, sides)
+	}
+	if color := o.Color.colorOption(); color != "" {
+		options = append(options, color)
+	}
+	if o.Pages != "" {
+		options = append(options, "page-ranges="+o.Pages)
+	}
+	return dedupeOptions(append(options, o.Extra...))
+}
+
+// dedupeOptions drops key=value options whose key an earlier option set
+func dedupeOptions(options []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, option := range options {
+		key, _, _ := strings.Cut(option, "=")
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, option)
+		}
+	}
+	return out
+}
+
+// lpArgs builds the lp arguments for these options (excluding title and file)
+func (o PrintOptions) lpArgs() []string {
+	var args []string
+	if o.Printer != "" {
+		args = append(args, "-d", o.Printer)
+	}
+	copies := o.Copies
+	if copies < 1 {
+		copies = 1
+	}
+	args = append(args, "-n", fmt.Sprintf("%d", copies))
+	for _, option := range o.cupsOptions() {
+		args = append(args, "-o", option)
+	}
+	return args
+}
+
+// shellJoin renders argv as a command line that a POSIX shell parses back
+// into the same arguments, single-quoting any argument that needs it
+func shellJoin(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes s for a POSIX shell unless it only has safe characters
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// describe summarizes the options that are set, e.g. "2 copies, printer Office"
+func (o PrintOptions) describe() string {
+	var parts []string
+	if o.Copies > 1 {
+		parts = append(parts, fmt.Sprintf("%d copies", o.Copies))
+	}
+	if o.Printer != "" {
+		parts = append(parts, "printer "+o.Printer)
+	}
+	if o.Duplex != DuplexNone {
+		parts = append(parts, o.Duplex.String())
+	}
+	if o.Color != ColorAuto {
+		parts = append(parts, o.Color.String())
+	}
+	if o.Pages != "" {
+		parts = append(parts, "pages "+o.Pages)
+	}
+	if len(o.Extra) > 0 {
+		parts = append(parts, strings.Join(o.Extra, " "))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
 // submitPrintJobCmd creates a command that sends a file to the printer
-func submitPrintJobCmd(opID string, filePath string, copies int) tea.Cmd {
+func submitPrintJobCmd(opID string, filePath string, opts PrintOptions) tea.Cmd {
 	return func() tea.Msg {
 		// Add a small random delay to stagger concurrent submissions
 		// This helps prevent overwhelming the print spooler
@@ -77,39 +276,22 @@ func submitPrintJobCmd(opID string, filePath string, copies int) tea.Cmd {
 			}
 		}
 
-		// Execute lp command with -t to set job title (filename)
-		// Use -n for number of copies
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		fileName := filepath.Base(filePath)
-		if copies < 1 {
-			copies = 1
+		if safeMode.Load() {
+			return PrintStatusMsg{FileID: opID, Status: StatusSafeMode}
+		}
+		if dryRun {
+			return PrintStatusMsg{FileID: opID, Status: StatusSent, Command: shellJoin(lpCommand(filePath, opts))}
 		}
-		cmd := exec.CommandContext(ctx, "lp", "-n", fmt.Sprintf("%d", copies), "-t", fileName, filePath)
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
 
-		err := cmd.Run()
+		jobID, err := backend.Submit(filePath, opts)
 		if err != nil {
-			if ctx.Err() == context.DeadlineExceeded {
-				return PrintStatusMsg{
-					FileID: opID,
-					Status: StatusFailed,
-					Error:  fmt.Errorf("print command timed out after 10 seconds"),
-				}
-			}
 			return PrintStatusMsg{
 				FileID: opID,
 				Status: StatusFailed,
-				Error:  fmt.Errorf("failed to print: %v - %s", err, stderr.String()),
+				Error:  err,
 			}
 		}
 
-		// Parse job ID from lp output: "request id is PRINTER-123 (1 file(s))"
-		jobID := parseJobIDFromLpOutput(stdout.String())
-
 		return PrintStatusMsg{
 			FileID:      opID,
 			Status:      StatusSent,
@@ -157,16 +339,16 @@ func processPrintJob(opID string, filePath string) PrintStatusMsg {
 		}
 	}
 
+	fileName := filepath.Base(filePath)
+	if dryRun {
+		return PrintStatusMsg{FileID: opID, Status: StatusSent, Command: shellJoin([]string{"lp", "-t", fileName, filePath})}
+	}
+
 	// Execute lp command with -t to set job title (filename)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	fileName := filepath.Base(filePath)
-	cmd := exec.CommandContext(ctx, "lp", "-t", fileName, filePath)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
+	_, stderr, err := runner.Run(ctx, "lp", "-t", fileName, filePath)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return PrintStatusMsg{
@@ -178,7 +360,7 @@ func processPrintJob(opID string, filePath string) PrintStatusMsg {
 		return PrintStatusMsg{
 			FileID: opID,
 			Status: StatusFailed,
-			Error:  fmt.Errorf("failed to print: %v - %s", err, stderr.String()),
+			Error:  fmt.Errorf("failed to print: %v - %s", err, stderr),
 		}
 	}
 
@@ -195,8 +377,7 @@ func CheckPrinterAvailable() (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "lpstat", "-p")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, "lpstat", "-p")
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return false, fmt.Errorf("printer check timed out")
@@ -227,7 +408,7 @@ func sequentialPrintCmd(operations []PrintOperation) tea.Cmd {
 			}
 		},
 		// Then submit the print job (default 1 copy for sequential operations)
-		submitPrintJobCmd(first.ID, first.FilePath, 1),
+		submitPrintJobCmd(first.ID, first.FilePath, PrintOptions{Copies: 1}),
 		// Then wait a bit
 		tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
 			return nil // Just for delay
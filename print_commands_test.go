@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRunner records every command instead of running it and answers with
+// canned output, keyed by program name
+type fakeRunner struct {
+	mu     sync.Mutex
+	calls  [][]string
+	stdout map[string]string
+	err    map[string]error
+}
+
+func (f *fakeRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, append([]string{name}, args...))
+	return []byte(f.stdout[name]), nil, f.err[name]
+}
+
+func (f *fakeRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	stdout, _, err := f.Run(ctx, name, args...)
+	return stdout, err
+}
+
+// errFake stands in for a command exiting with an error
+var errFake = errors.New("exit status 1")
+
+// useFakeRunner swaps in a fake runner for the duration of the test
+func useFakeRunner(t *testing.T) *fakeRunner {
+	t.Helper()
+	fake := &fakeRunner{stdout: make(map[string]string), err: make(map[string]error)}
+	saved := runner
+	runner = fake
+	t.Cleanup(func() { runner = saved })
+	return fake
+}
+
+func TestLpArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts PrintOptions
+		want []string
+	}{
+		{"defaults", PrintOptions{}, []string{"-n", "1"}},
+		{"copies", PrintOptions{Copies: 3}, []string{"-n", "3"}},
+		{"printer", PrintOptions{Printer: "Office", Copies: 1}, []string{"-d", "Office", "-n", "1"}},
+		{"duplex long", PrintOptions{Copies: 1, Duplex: DuplexLong},
+			[]string{"-n", "1", "-o", "sides=two-sided-long-edge"}},
+		{"duplex short", PrintOptions{Copies: 1, Duplex: DuplexShort},
+			[]string{"-n", "1", "-o", "sides=two-sided-short-edge"}},
+		{"grayscale", PrintOptions{Copies: 1, Color: ColorGray},
+			[]string{"-n", "1", "-o", "ColorModel=Gray"}},
+		{"pages", PrintOptions{Copies: 1, Pages: "2-5,8"},
+			[]string{"-n", "1", "-o", "page-ranges=2-5,8"}},
+		{"everything", PrintOptions{Printer: "Office", Copies: 2, Duplex: DuplexLong, Color: ColorGray,
+			Pages: "1-3", Extra: []string{"media=A4"}},
+			[]string{"-d", "Office", "-n", "2", "-o", "sides=two-sided-long-edge", "-o", "ColorModel=Gray",
+				"-o", "page-ranges=1-3", "-o", "media=A4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.lpArgs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lpArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCupsSubmitRunsLp(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.stdout["lp"] = "request id is Office-216 (1 file(s))\n"
+
+	opts := PrintOptions{Printer: "Office", Copies: 2, Duplex: DuplexShort, Color: ColorGray, Pages: "2-5"}
+	jobID, err := cupsBackend{}.Submit("/tmp/my report.pdf", opts)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if jobID != "216" {
+		t.Errorf("Submit() job ID = %q, want %q", jobID, "216")
+	}
+
+	want := []string{"lp", "-d", "Office", "-n", "2", "-o", "sides=two-sided-short-edge",
+		"-o", "ColorModel=Gray", "-o", "page-ranges=2-5", "-t", "my report.pdf", "/tmp/my report.pdf"}
+	if len(fake.calls) != 1 || !reflect.DeepEqual(fake.calls[0], want) {
+		t.Errorf("Submit() ran %q, want %q", fake.calls, want)
+	}
+}
+
+func TestCupsSubmitReportsFailure(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.err["lp"] = errFake
+
+	if _, err := (cupsBackend{}).Submit("/tmp/a.pdf", PrintOptions{Copies: 1}); err == nil ||
+		!strings.Contains(err.Error(), "failed to print") {
+		t.Errorf("Submit() error = %v, want a print failure", err)
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	tests := []struct {
+		argv []string
+		want string
+	}{
+		{[]string{"lp", "-n", "2", "/tmp/a.pdf"}, "lp -n 2 /tmp/a.pdf"},
+		{[]string{"lp", "/tmp/my report.pdf"}, "lp '/tmp/my report.pdf'"},
+		{[]string{"lp", "/tmp/report (final).pdf"}, "lp '/tmp/report (final).pdf'"},
+		{[]string{"lp", "/tmp/café.pdf"}, "lp '/tmp/café.pdf'"},
+		{[]string{"lp", "/tmp/it's.pdf"}, `lp '/tmp/it'\''s.pdf'`},
+		{[]string{"lp", "-o", "media=A4", ""}, "lp -o media=A4 ''"},
+	}
+	for _, tt := range tests {
+		if got := shellJoin(tt.argv); got != tt.want {
+			t.Errorf("shellJoin(%q) = %s, want %s", tt.argv, got, tt.want)
+		}
+	}
+}
+
+func TestSafeModeStatusLabel(t *testing.T) {
+	op := PrintOperation{FileName: "report.pdf", Status: StatusSafeMode}
+	if got := op.statusLabel(); got != "SAFE MODE — not printed" {
+		t.Errorf("statusLabel() = %q, want the safe mode label", got)
+	}
+	op.Status = StatusSent
+	if got := op.statusLabel(); got != "sent" {
+		t.Errorf("statusLabel() = %q, want sent", got)
+	}
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
+	"sort"
 	"time"
 )
 
@@ -12,29 +12,80 @@ type PrintOperation struct {
 	ID        string
 	FilePath  string
 	FileName  string
+	Printer   string // Destination printer, "" for the system default
+	Copies    int
+	Duplex    DuplexMode
+	Color     ColorMode
+	Pages     string // Page range the job was limited to, "" for all
+	Extra     []string // Raw CUPS options the job was submitted with
 	Status    PrintStatus
 	Error     error
 	StartedAt time.Time
 	UpdatedAt time.Time
 	SystemJobID string // The actual system print job ID if successfully submitted
+	SeenInQueue bool   // SystemJobID has shown up in the system queue
+	Command     string // lp command recorded instead of run (--dry-run)
 }
 
+// isTerminal reports whether the operation is finished (sent, completed,
+// failed, canceled or skipped by safe mode)
+func (op PrintOperation) isTerminal() bool {
+	return op.Status == StatusSent || op.Status == StatusCompleted ||
+		op.Status == StatusFailed || op.Status == StatusCanceled || op.Status == StatusSafeMode
+}
+
+// statusLabel is the operation's status as shown in the detail overlay and report
+func (op PrintOperation) statusLabel() string {
+	if op.Status == StatusSafeMode {
+		return "SAFE MODE — not printed"
+	}
+	return string(op.Status)
+}
+
+// prunePrintOps drops the oldest finished operations (by UpdatedAt) once there
+// are more than config.MaxPrintOps; in-flight operations are always kept
+func (m *model) prunePrintOps() {
+	excess := len(m.printOps) - config.MaxPrintOps
+	if config.MaxPrintOps <= 0 || excess <= 0 {
+		return
+	}
+
+	var finished []int
+	for i, op := range m.printOps {
+		if op.isTerminal() {
+			finished = append(finished, i)
+		}
+	}
+	sort.SliceStable(finished, func(a, b int) bool {
+		return m.printOps[finished[a]].UpdatedAt.Before(m.printOps[finished[b]].UpdatedAt)
+	})
+	if excess > len(finished) {
+		excess = len(finished)
+	}
+
+	drop := make(map[int]bool, excess)
+	for _, i := range finished[:excess] {
+		drop[i] = true
+	}
+	kept := m.printOps[:0]
+	for i, op := range m.printOps {
+		if !drop[i] {
+			kept = append(kept, op)
+		}
+	}
+	m.printOps = kept
+	m.clampCursors()
+}
 
 func (m *model) formatPrintFileName(op PrintOperation) string {
-	// Show relative path from current directory
+	// Show relative path from current directory: just the name for files in
+	// it, otherwise the relative path
 	relPath, err := filepath.Rel(m.currentDir, op.FilePath)
 	if err != nil {
 		// If we can't get relative path, just show the filename
-		return op.FileName
-	}
-	
-	// If file is in current directory, just show the name
-	if !strings.Contains(relPath, string(filepath.Separator)) && !strings.HasPrefix(relPath, "..") {
-		return relPath
+		return sanitizeForDisplay(op.FileName)
 	}
-	
-	// Otherwise show the relative path
-	return relPath
+	return sanitizeForDisplay(relPath)
 }
 
 func (m *model) formatTimeAgo(t time.Time) string {
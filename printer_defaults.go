@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// PrinterDefaults remembers the options last used successfully on each printer,
+// so staging for that printer starts from them. Persisted as
+// printer_defaults.json in the data dir, or kept in memory when it isn't writable.
+type PrinterDefaults struct {
+	mu        sync.Mutex
+	path      string // "" when running in memory only
+	byPrinter map[string]PrintOptions
+}
+
+// loadPrinterDefaults reads the remembered options from the data dir
+func loadPrinterDefaults() *PrinterDefaults {
+	d := &PrinterDefaults{byPrinter: make(map[string]PrintOptions)}
+
+	dir, err := dataDir()
+	if err != nil || ensureWritableDir(dir) != nil {
+		return d
+	}
+	d.path = filepath.Join(dir, "printer_defaults.json")
+	if data, err := os.ReadFile(d.path); err == nil {
+		// A corrupt file just starts over
+		_ = json.Unmarshal(data, &d.byPrinter)
+		if d.byPrinter == nil {
+			d.byPrinter = make(map[string]PrintOptions)
+		}
+	}
+	return d
+}
+
+// Get returns the remembered options for a printer
+func (d *PrinterDefaults) Get(printer string) (PrintOptions, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	opts, ok := d.byPrinter[printer]
+	return opts, ok
+}
+
+// Remember stores the options of a successful print on printer and saves
+func (d *PrinterDefaults) Remember(printer string, opts PrintOptions) {
+	if printer == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	opts.Printer = printer
+	d.byPrinter[printer] = opts
+	if d.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(d.byPrinter, "", "  ")
+	if err != nil {
+		return
+	}
+	if writeFileAtomic(d.path, data) != nil {
+		d.path = ""
+	}
+}
+
+// applyPrinterDefaults starts a staged file from the options last used on
+// the printer it will print on: its own, else the selected one
+func (m *model) applyPrinterDefaults(file *StagedFile) bool {
+	printer := m.destination(file.Printer)
+	if printer == "" || m.printerDefaults == nil {
+		return false
+	}
+	opts, ok := m.printerDefaults.Get(printer)
+	if !ok {
+		return false
+	}
+	if opts.Copies > 0 {
+		file.Copies = opts.Copies
+	}
+	file.Duplex = opts.Duplex
+	file.Color = opts.Color
+	file.ExtraOptions = append([]string(nil), opts.Extra...)
+	return true
+}
+
+// applySelectedPrinterDefaults restarts the staged files that follow the
+// selected printer from its remembered options, after the selection changed
+func (m *model) applySelectedPrinterDefaults() {
+	for i := range m.stagedFiles {
+		if m.stagedFiles[i].Printer == "" {
+			m.applyPrinterDefaults(&m.stagedFiles[i])
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRememberedOptionsApplyWithoutDirectoryDefaults(t *testing.T) {
+	m := newTestModel(t)
+	m.printerDefaults.Remember("Office", PrintOptions{Copies: 2, Duplex: DuplexLong, Color: ColorGray, Extra: []string{"media=A4"}})
+	m.selectedPrinter = "Office"
+
+	m.stageFile("a.pdf", filepath.Join(t.TempDir(), "a.pdf"), "/nowhere", 10)
+	file := m.stagedFiles[len(m.stagedFiles)-1]
+	if file.Copies != 2 || file.Duplex != DuplexLong || file.Color != ColorGray || len(file.ExtraOptions) != 1 {
+		t.Errorf("staged file = %+v, want Office's remembered options", file)
+	}
+}
+
+func TestRememberedOptionsApplyWhenPrinterAssigned(t *testing.T) {
+	m := newTestModel(t)
+	m.printerDefaults.Remember("Lab", PrintOptions{Copies: 3, Color: ColorGray})
+	m.stageFile("a.pdf", filepath.Join(t.TempDir(), "a.pdf"), "/nowhere", 10)
+
+	m.pickerPurpose = PickDestination
+	m.choosePrinter("Lab")
+	if file := m.stagedFiles[0]; file.Copies != 3 || file.Color != ColorGray {
+		t.Errorf("after picking Lab, staged file = %+v, want Lab's remembered options", file)
+	}
+
+	m.printerDefaults.Remember("Office", PrintOptions{Copies: 1, Duplex: DuplexShort})
+	m.duplicateBatchTo("Office")
+	if clone := m.stagedFiles[1]; clone.Printer != "Office" || clone.Duplex != DuplexShort || clone.Copies != 1 {
+		t.Errorf("clone for Office = %+v, want Office's remembered options", clone)
+	}
+}
+
+func TestSentJobRemembersDuplexAndColor(t *testing.T) {
+	m := newTestModel(t)
+	m.printOps = []PrintOperation{{ID: "op1", Printer: "Office", Copies: 2, Duplex: DuplexLong, Color: ColorGray, Status: StatusSending}}
+
+	m.update(PrintStatusMsg{FileID: "op1", Status: StatusSent, SystemJobID: "5"})
+	opts, ok := m.printerDefaults.Get("Office")
+	if !ok || opts.Copies != 2 || opts.Duplex != DuplexLong || opts.Color != ColorGray {
+		t.Errorf("remembered %+v, %v; want copies, duplex and color of the sent job", opts, ok)
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PickerPurpose says what choosing a printer in the picker does
+type PickerPurpose int
+
+const (
+	PickReprint        PickerPurpose = iota // Reprint the job from the detail overlay or history
+	PickDestination                         // Choose where staged files are printed
+	PickDuplicateBatch                      // Stage a second set of the batch for another printer
+)
+
+var pickerTitles = map[PickerPurpose]string{
+	PickReprint:        "Reprint To…",
+	PickDestination:    "Print To…",
+	PickDuplicateBatch: "Also Print On…",
+}
+
+// openPrinterPicker shows the printer picker and rediscovers printers in the background
+func (m *model) openPrinterPicker(purpose PickerPurpose) tea.Cmd {
+	m.pickerPurpose = purpose
+	m.pickerCursor = 0
+	for i, p := range m.printers {
+		if p.Name == m.selectedPrinter || (m.selectedPrinter == "" && p.IsDefault) {
+			m.pickerCursor = i
+			break
+		}
+	}
+	m.overlay = OverlayPrinterPicker
+	return refreshPrintersCmd()
+}
+
+// updatePrinterPicker handles keys while the printer picker is open
+func (m model) updatePrinterPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.overlay = OverlayNone
+
+	case "up", "k":
+		if m.pickerCursor > 0 {
+			m.pickerCursor--
+		}
+
+	case "down", "j":
+		if m.pickerCursor < len(m.printers)-1 {
+			m.pickerCursor++
+		}
+
+	case "r":
+		// Pick up printers added or removed since the app started
+		m.setStatus("Refreshing printers…")
+		return m, refreshPrintersCmd()
+
+	case "/":
+		// Type part of a long queue name instead of scrolling to it
+		cmd := m.openPrompt(PromptFindPrinter, pickerTitles[m.pickerPurpose], "part of the printer name", "")
+		return m, cmd
+
+	case "enter":
+		if m.pickerCursor < len(m.printers) {
+			m.overlay = OverlayNone
+			return m, m.choosePrinter(m.printers[m.pickerCursor].Name)
+		}
+	}
+	return m, nil
+}
+
+// choosePrinter applies the picked printer according to the picker's purpose
+func (m *model) choosePrinter(name string) tea.Cmd {
+	switch m.pickerPurpose {
+	case PickReprint:
+		return m.reprintTo(name)
+	case PickDestination:
+		m.selectPrinter(name)
+		m.applySelectedPrinterDefaults()
+	case PickDuplicateBatch:
+		m.confirmPrinter = name
+		m.openConfirm(ConfirmDuplicateBatch, fmt.Sprintf(
+			"Stage all %d file(s) again for %s?\nP will then print both sets.", len(m.stagedFiles), name))
+	}
+	return nil
+}
+
+// matchPrinter resolves query to a printer name: an exact name (ignoring case)
+// wins, otherwise query must be part of exactly one printer's name
+func matchPrinter(query string, printers []PrinterInfo) (string, error) {
+	if query == "" {
+		return "", fmt.Errorf("enter a printer name")
+	}
+	var matches []string
+	for _, p := range printers {
+		if strings.EqualFold(p.Name, query) {
+			return p.Name, nil
+		}
+		if strings.Contains(strings.ToLower(p.Name), strings.ToLower(query)) {
+			matches = append(matches, p.Name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no printer matches %q", query)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%q matches %s", query, strings.Join(matches, ", "))
+}
+
+// selectPrinter makes name the destination for staged files; picking the
+// system default goes back to following it
+func (m *model) selectPrinter(name string) {
+	m.selectedPrinter = name
+	for _, p := range m.printers {
+		if p.Name == name && p.IsDefault {
+			m.selectedPrinter = ""
+		}
+	}
+	s := m.settings
+	s.Printer = m.selectedPrinter
+	m.rememberSettings(s)
+	m.setStatus("Printing to " + name)
+}
+
+// checkSelectedPrinter goes back to the system default when the selected
+// printer is no longer listed, so jobs don't fail later. The saved choice is
+// kept: the printer may only be switched off for now. An empty list is a
+// failed lookup, not every printer gone, and changes nothing.
+func (m *model) checkSelectedPrinter() {
+	if m.selectedPrinter == "" || len(m.printers) == 0 {
+		return
+	}
+	for _, p := range m.printers {
+		if p.Name == queueName(m.selectedPrinter) {
+			return
+		}
+	}
+	m.setError(fmt.Sprintf("Printer %s is no longer available; printing to the system default", m.selectedPrinter))
+	m.selectedPrinter = ""
+}
+
+// destination returns the printer a job goes to: its own printer if set,
+// else the selected printer ("" for the system default)
+func (m model) destination(printer string) string {
+	if printer != "" {
+		return printer
+	}
+	return m.selectedPrinter
+}
+
+// currentPrinter returns the selected printer's info, or the system default's.
+// Without lpstat (e.g. on Windows) the default comes from the discovered printers.
+func (m model) currentPrinter() PrinterInfo {
+	if m.selectedPrinter == "" {
+		if printer := getDefaultPrinter(); printer.Name != "Unknown" {
+			return printer
+		}
+	}
+	for _, p := range m.printers {
+		if p.Name == m.selectedPrinter || (m.selectedPrinter == "" && p.IsDefault) {
+			return p
+		}
+	}
+	if m.selectedPrinter != "" {
+		return PrinterInfo{Name: m.selectedPrinter}
+	}
+	return getDefaultPrinter()
+}
+
+// reprintTo resubmits the file remembered from the detail overlay or history
+// to another printer
+func (m *model) reprintTo(printer string) tea.Cmd {
+	if m.reprintPath == "" {
+		return nil
+	}
+	cmd := m.submitFile(m.reprintPath, PrintOptions{Printer: printer, Copies: m.reprintCopies})
+	if cmd != nil {
+		m.setStatus(fmt.Sprintf("Reprinting %s on %s", m.reprintName, printer))
+	}
+	return cmd
+}
+
+// openReprintPicker remembers the detail overlay's file and asks which printer to reprint it on
+func (m *model) openReprintPicker() tea.Cmd {
+	path, name, copies := m.detailSource()
+	if path == "" {
+		m.setError("No source file known for this job")
+		return nil
+	}
+	m.reprintPath = path
+	m.reprintName = name
+	m.reprintCopies = copies
+	return m.openPrinterPicker(PickReprint)
+}
+
+// clampPickerCursor keeps the picker cursor valid after the printer list changes
+func (m *model) clampPickerCursor() {
+	if m.pickerCursor >= len(m.printers) {
+		m.pickerCursor = len(m.printers) - 1
+	}
+	if m.pickerCursor < 0 {
+		m.pickerCursor = 0
+	}
+}
+
+// renderPrinterPicker renders the printer list with states
+func (m model) renderPrinterPicker() string {
+	var content strings.Builder
+	content.WriteString(helpWindowTitleStyle.Render(pickerTitles[m.pickerPurpose]))
+	content.WriteString("\n\n")
+
+	if len(m.printers) == 0 {
+		content.WriteString(dimStyle.Render("No printers found"))
+		content.WriteString("\n")
+	}
+	for i, p := range m.printers {
+		label := p.Name
+		if p.IsDefault {
+			label += " (default)"
+		}
+		statusStyle := printerStatusIdleStyle
+		if p.Status != "idle" {
+			statusStyle = printerStatusActiveStyle
+		}
+		line := renderSelectable(i == m.pickerCursor, 2, label, selectedFileStyle, normalStyle)
+		content.WriteString(line + statusStyle.Render(" - "+p.Status))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(helpActionStyle.Render("enter: choose • /: find • r: refresh • esc: cancel"))
+
+	return helpWindowStyle.Render(content.String())
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRefreshDropsRemovedSelectedPrinter(t *testing.T) {
+	m := newTestModel(t)
+	m.selectedPrinter = "Gone"
+
+	// A failed lookup lists nothing and must not change the selection
+	updated, _ := m.update(printersRefreshedMsg{})
+	m = updated.(model)
+	if m.selectedPrinter != "Gone" {
+		t.Fatalf("empty printer list cleared the selection")
+	}
+
+	updated, _ = m.update(printersRefreshedMsg{printers: []PrinterInfo{{Name: "Office", IsDefault: true}}})
+	m = updated.(model)
+	if m.selectedPrinter != "" {
+		t.Errorf("selectedPrinter = %q after it disappeared, want the system default", m.selectedPrinter)
+	}
+	if !m.statusIsErr || !strings.Contains(m.statusMsg, "Gone") {
+		t.Errorf("status = %q, want a warning naming the printer", m.statusMsg)
+	}
+}
+
+func TestRefreshKeepsListedSelectedPrinter(t *testing.T) {
+	m := newTestModel(t)
+	m.selectedPrinter = "Office"
+
+	updated, _ := m.update(printersRefreshedMsg{printers: []PrinterInfo{{Name: "Office"}, {Name: "Lab"}}})
+	if m = updated.(model); m.selectedPrinter != "Office" {
+		t.Errorf("selectedPrinter = %q, want Office kept", m.selectedPrinter)
+	}
+}
+
+func TestPickerRefreshKey(t *testing.T) {
+	m := newTestModel(t)
+	m.overlay = OverlayPrinterPicker
+
+	if _, cmd := pressKey(t, m, "r"); cmd == nil {
+		t.Error("r in the printer picker didn't refresh the printers")
+	}
+}
+
+func TestHistoryReprintToPickedPrinter(t *testing.T) {
+	m := newTestModel(t)
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.printers = []PrinterInfo{{Name: "Office", IsDefault: true}, {Name: "Lab"}}
+	m.historyEntries = []HistoryEntry{{FilePath: path, FileName: "report.pdf", Copies: 2}}
+	m.overlay = OverlayHistory
+
+	m, _ = pressKey(t, m, "R")
+	if m.overlay != OverlayPrinterPicker || m.pickerPurpose != PickReprint {
+		t.Fatalf("R in history opened overlay %v purpose %v, want the reprint picker", m.overlay, m.pickerPurpose)
+	}
+	m, _ = pressKey(t, m, "down")
+	m, cmd := pressKey(t, m, "enter")
+	if cmd == nil || len(m.printOps) != 1 {
+		t.Fatalf("choosing a printer sent %d jobs, want 1", len(m.printOps))
+	}
+	if op := m.printOps[0]; op.FilePath != path || op.Printer != "Lab" || op.Copies != 2 {
+		t.Errorf("reprint = %s on %q x%d, want %s on Lab x2", op.FilePath, op.Printer, op.Copies, path)
+	}
+}
+
+func TestHistoryReprintReportsMissingFile(t *testing.T) {
+	m := newTestModel(t)
+	m.historyEntries = []HistoryEntry{{FilePath: filepath.Join(t.TempDir(), "gone.pdf"), FileName: "gone.pdf"}}
+	m.overlay = OverlayHistory
+
+	m, _ = pressKey(t, m, "R")
+	if m.overlay != OverlayHistory {
+		t.Errorf("overlay = %v, want history kept open", m.overlay)
+	}
+	if !m.statusIsErr || !strings.Contains(m.statusMsg, "gone.pdf") {
+		t.Errorf("status = %q, want an error naming the missing file", m.statusMsg)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// printerQueue is one printer's row in the all-printers overview
+type printerQueue struct {
+	PrinterInfo
+	Jobs int // Jobs waiting or printing on this printer
+}
+
+// printerQueuesMsg is a snapshot of every printer's state and queue length
+type printerQueuesMsg struct {
+	Queues []printerQueue
+	Err    error
+}
+
+// printerQueuesCmd lists the printers with `lpstat -p` and counts each one's
+// jobs with `lpstat -o`, whose job IDs are "PRINTER-NNN"
+func printerQueuesCmd() tea.Cmd {
+	return func() tea.Msg {
+		printers := getAvailablePrinters()
+		if len(printers) == 0 {
+			return printerQueuesMsg{Err: fmt.Errorf("no printers found")}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		output, err := runner.Output(ctx, "lpstat", "-o")
+		if err != nil {
+			return printerQueuesMsg{Err: err}
+		}
+		counts := countJobsByPrinter(string(output))
+
+		queues := make([]printerQueue, len(printers))
+		for i, p := range printers {
+			queues[i] = printerQueue{PrinterInfo: p, Jobs: counts[p.Name]}
+		}
+		return printerQueuesMsg{Queues: queues}
+	}
+}
+
+// countJobsByPrinter counts the jobs in `lpstat -o` output per printer:
+//
+//	Office-123    adrian    155648   Mon 01 Jan 2024 10:00:00
+func countJobsByPrinter(output string) map[string]int {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		dash := strings.LastIndex(fields[0], "-")
+		if _, ok := parseJobIDField(fields[0]); ok && dash > 0 {
+			counts[fields[0][:dash]]++
+		}
+	}
+	return counts
+}
+
+// openPrinterQueues shows every printer's state and queue length; the
+// overlay refreshes on each tick while open
+func (m *model) openPrinterQueues() tea.Cmd {
+	m.printerQueuesLoading = true
+	m.overlay = OverlayPrinterQueues
+	return printerQueuesCmd()
+}
+
+// renderPrinterQueues renders the all-printers overview
+func (m model) renderPrinterQueues() string {
+	var content strings.Builder
+	content.WriteString(helpWindowTitleStyle.Render("All Printers"))
+	content.WriteString("\n\n")
+
+	switch {
+	case m.printerQueuesLoading && len(m.printerQueues.Queues) == 0:
+		content.WriteString(dimStyle.Render("Querying CUPS…"))
+		content.WriteString("\n")
+	case m.printerQueues.Err != nil:
+		content.WriteString(errorStyle.Render(fmt.Sprintf("lpstat failed: %v", m.printerQueues.Err)))
+		content.WriteString("\n")
+	default:
+		totalJobs := 0
+		for _, q := range m.printerQueues.Queues {
+			marker := "  "
+			if q.IsDefault {
+				marker = "★ "
+			}
+			jobs := dimStyle.Render("no jobs")
+			if q.Jobs > 0 {
+				jobs = overlayValueStyle.Render(fmt.Sprintf("%d job(s)", q.Jobs))
+			}
+			state := printerStatusIdleStyle.Render(q.Status)
+			if q.Status != "idle" {
+				state = printerStatusActiveStyle.Render(q.Status)
+			}
+			content.WriteString(overlayLabelStyle.Copy().Width(24).Render(truncate(marker+sanitizeForDisplay(q.Name), 22)))
+			content.WriteString(jobs + "  " + state)
+			if q.Reason != "" {
+				content.WriteString(dimStyle.Render(" (" + truncate(sanitizeForDisplay(q.Reason), 30) + ")"))
+			}
+			content.WriteString("\n")
+			totalJobs += q.Jobs
+		}
+		content.WriteString("\n")
+		content.WriteString(dimStyle.Render(fmt.Sprintf("%d printer(s), %d job(s) queued", len(m.printerQueues.Queues), totalJobs)))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(helpActionStyle.Render("refreshes every second • esc: close"))
+	return helpWindowStyle.Render(content.String())
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// printerStateMsg is the live CUPS state of one printer, for the printer status overlay
+type printerStateMsg struct {
+	Name    string
+	State   string   // "idle", "disabled since Mon 01 Jan 10:00 -", ...
+	Message string   // State message set by CUPS or the driver, e.g. "Media jam"
+	Alerts  []string // printer-state-reasons, e.g. "media-jam-error"
+	Err     error
+}
+
+// printerStateCmd queries `lpstat -l -p NAME` for the printer's state and reasons
+func printerStateCmd(name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		output, err := runner.Output(ctx, "lpstat", "-l", "-p", name)
+		if err != nil {
+			return printerStateMsg{Name: name, Err: err}
+		}
+		msg := parsePrinterState(string(output))
+		msg.Name = name
+		return msg
+	}
+}
+
+// parsePrinterState parses the long form of lpstat -p for a single printer:
+//
+//	printer Office disabled since Mon 01 Jan 2024 10:00:00 -
+//		Paused
+//		Description: Office
+//		Alerts: media-jam-error none
+//
+// The message is the first indented line that isn't a "Field: value" pair.
+func parsePrinterState(output string) printerStateMsg {
+	var msg printerStateMsg
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "printer "):
+			fields := strings.Fields(line)
+			if len(fields) > 2 {
+				msg.State = strings.TrimSuffix(strings.TrimPrefix(strings.Join(fields[2:], " "), "is "), " -")
+			}
+		case strings.HasPrefix(trimmed, "Alerts:"):
+			for _, alert := range strings.Fields(strings.TrimPrefix(trimmed, "Alerts:")) {
+				if alert != "none" {
+					msg.Alerts = append(msg.Alerts, alert)
+				}
+			}
+		case trimmed == "" || strings.Contains(trimmed, ":"):
+			// Other fields (Description, Location, Connection, ...)
+		case msg.Message == "":
+			msg.Message = trimmed
+		}
+	}
+	return msg
+}
+
+// openPrinterState shows the CUPS state of the current printer, queried live
+func (m *model) openPrinterState() tea.Cmd {
+	name := m.currentPrinter().Name
+	if name == "" || name == "Unknown" || name == "No printer" {
+		m.setError("No printer to show")
+		return nil
+	}
+	m.printerState = printerStateMsg{Name: name}
+	m.printerStateLoading = true
+	m.overlay = OverlayPrinterState
+	return printerStateCmd(name)
+}
+
+// renderPrinterState renders the printer status overlay
+func (m model) renderPrinterState() string {
+	state := m.printerState
+
+	var content strings.Builder
+	content.WriteString(helpWindowTitleStyle.Render("Printer " + state.Name))
+	content.WriteString("\n\n")
+
+	row := func(label, value string) {
+		content.WriteString(overlayLabelStyle.Render(label))
+		content.WriteString(overlayValueStyle.Render(sanitizeForDisplay(value)))
+		content.WriteString("\n")
+	}
+
+	switch {
+	case m.printerStateLoading:
+		content.WriteString(dimStyle.Render("Querying CUPS…"))
+		content.WriteString("\n")
+	case state.Err != nil:
+		content.WriteString(errorStyle.Render(fmt.Sprintf("lpstat failed: %v", state.Err)))
+		content.WriteString("\n")
+	default:
+		row("State", state.State)
+		if state.Message != "" {
+			row("Message", state.Message)
+		}
+		if len(state.Alerts) > 0 {
+			row("Reasons", strings.Join(state.Alerts, ", "))
+		}
+		if state.Message == "" && len(state.Alerts) == 0 {
+			content.WriteString(dimStyle.Render("CUPS reports no problems"))
+			content.WriteString("\n")
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(helpActionStyle.Render("r: refresh • esc: close"))
+	return helpWindowStyle.Render(content.String())
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PromptAction is what submitting the text prompt overlay does
+type PromptAction int
+
+const (
+	PromptExtraOptions PromptAction = iota // Edit promptIndex's extra CUPS options
+	PromptBatchSets                        // Number of complete sets of the staged batch
+	PromptNote                             // Edit promptIndex's note
+	PromptSplitJobs                        // Stage promptIndex as N separate jobs
+	PromptRename                           // Rename the file at promptPath
+	PromptMkdir                            // Create a directory in the browsed directory
+	PromptFindPrinter                      // Choose the printer matching part of its name
+	PromptPages                            // Edit promptIndex's page range
+)
+
+// openPrompt shows a one-line text prompt prefilled with value
+func (m *model) openPrompt(action PromptAction, title, placeholder, value string) tea.Cmd {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 256
+	ti.Width = 40
+	ti.SetValue(value)
+	ti.CursorEnd()
+
+	m.promptAction = action
+	m.promptTitle = title
+	m.promptInput = ti
+	m.promptErr = ""
+	m.overlay = OverlayPrompt
+	return m.promptInput.Focus()
+}
+
+// updatePrompt handles keys while the text prompt is open
+func (m model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.overlay = OverlayNone
+		return m, nil
+	case "enter":
+		cmd, err := m.submitPrompt(strings.TrimSpace(m.promptInput.Value()))
+		if err != nil {
+			// Keep the prompt open so the input can be fixed
+			m.promptErr = err.Error()
+			return m, nil
+		}
+		if m.overlay == OverlayPrompt {
+			m.overlay = OverlayNone
+		}
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	m.promptErr = ""
+	return m, cmd
+}
+
+// submitPrompt applies the entered value according to the prompt's action
+func (m *model) submitPrompt(value string) (tea.Cmd, error) {
+	switch m.promptAction {
+	case PromptExtraOptions:
+		return nil, m.setExtraOptions(m.promptIndex, value)
+	case PromptNote:
+		m.setNote(m.promptIndex, value)
+		return nil, nil
+	case PromptPages:
+		return nil, m.setPages(m.promptIndex, value)
+	case PromptSplitJobs:
+		jobs, err := strconv.Atoi(value)
+		if err != nil || jobs < 1 || jobs > maxBatchSets {
+			return nil, fmt.Errorf("enter a number from 1 to %d", maxBatchSets)
+		}
+		m.splitStagedJobs(m.promptIndex, jobs)
+		return nil, nil
+	case PromptRename:
+		return nil, m.renameFile(m.promptPath, value)
+	case PromptMkdir:
+		return nil, m.makeDirectory(value)
+	case PromptFindPrinter:
+		name, err := matchPrinter(value, m.printers)
+		if err != nil {
+			return nil, err
+		}
+		return m.choosePrinter(name), nil
+	case PromptBatchSets:
+		sets, err := parseSets(value)
+		if err != nil {
+			return nil, err
+		}
+		if sets == 1 {
+			return m.requestPrintStaged(false), nil
+		}
+		// The confirmation replaces the prompt once it closes
+		m.requestPrintSets(sets)
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// renderPrompt renders the prompt title, input and any validation error
+func (m model) renderPrompt() string {
+	var content strings.Builder
+	content.WriteString(helpWindowTitleStyle.Render(m.promptTitle))
+	content.WriteString("\n\n")
+	content.WriteString(m.promptInput.View())
+	content.WriteString("\n")
+	if m.promptErr != "" {
+		content.WriteString("\n")
+		content.WriteString(errorStyle.Render(m.promptErr))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(helpActionStyle.Render("enter: apply • esc: cancel"))
+	return helpWindowStyle.Render(content.String())
+}
+
+// parseExtraOptions splits "media=A4 sides=two-sided-long-edge" into
+// key=value pairs for lp -o, rejecting anything that isn't key=value
+func parseExtraOptions(s string) ([]string, error) {
+	var options []string
+	for _, field := range strings.Fields(s) {
+		eq := strings.Index(field, "=")
+		if eq <= 0 || eq == len(field)-1 {
+			return nil, fmt.Errorf("%q is not key=value", field)
+		}
+		options = append(options, field)
+	}
+	return options, nil
+}
+
+// editExtraOptions opens the prompt for the staged file under the cursor
+func (m *model) editExtraOptions() tea.Cmd {
+	idx := m.stagedIndexAtCursor()
+	if idx == -1 {
+		return nil
+	}
+	file := m.stagedFiles[idx]
+	m.promptIndex = idx
+	return m.openPrompt(PromptExtraOptions, "CUPS Options: "+file.Name,
+		"key=value … (e.g. media=A4 fit-to-page=true)", strings.Join(file.ExtraOptions, " "))
+}
+
+// setExtraOptions validates and stores the extra options of a staged file
+func (m *model) setExtraOptions(idx int, value string) error {
+	options, err := parseExtraOptions(value)
+	if err != nil {
+		return err
+	}
+	if idx < 0 || idx >= len(m.stagedFiles) {
+		return nil
+	}
+	m.stagedFiles[idx].ExtraOptions = options
+	if len(options) == 0 {
+		m.setStatus("Cleared options for " + m.stagedFiles[idx].Name)
+	} else {
+		m.setStatus(fmt.Sprintf("%d option(s) set for %s", len(options), m.stagedFiles[idx].Name))
+	}
+	return nil
+}
+
+// editNote opens the note prompt for the staged file under the cursor
+func (m *model) editNote() tea.Cmd {
+	idx := m.stagedIndexAtCursor()
+	if idx == -1 {
+		return nil
+	}
+	file := m.stagedFiles[idx]
+	m.promptIndex = idx
+	return m.openPrompt(PromptNote, "Note: "+file.Name, "e.g. draft, for client", file.Note)
+}
+
+// setNote stores a staged file's note; an empty value clears it
+func (m *model) setNote(idx int, note string) {
+	if idx >= 0 && idx < len(m.stagedFiles) {
+		m.stagedFiles[idx].Note = note
+	}
+}
+
+// parsePageRanges validates a page range like "1,3,5-8" and returns it
+// without spaces. Pages count from 1 and ranges must not run backwards.
+func parsePageRanges(s string) (string, error) {
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return "", nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(first)
+		if err != nil || from < 1 {
+			return "", fmt.Errorf("%q is not a page or N-M range", part)
+		}
+		if !isRange {
+			continue
+		}
+		to, err := strconv.Atoi(last)
+		if err != nil || to < from {
+			return "", fmt.Errorf("%q is not a page or N-M range", part)
+		}
+	}
+	return s, nil
+}
+
+// editPages opens the page range prompt for the staged file under the cursor
+func (m *model) editPages() tea.Cmd {
+	idx := m.stagedIndexAtCursor()
+	if idx == -1 {
+		return nil
+	}
+	file := m.stagedFiles[idx]
+	m.promptIndex = idx
+	return m.openPrompt(PromptPages, "Pages: "+file.Name, "e.g. 2-5 or 1,3,7-9 (empty for all)", file.Pages)
+}
+
+// setPages validates and stores a staged file's page range; empty prints all pages
+func (m *model) setPages(idx int, value string) error {
+	pages, err := parsePageRanges(value)
+	if err != nil {
+		return err
+	}
+	if idx < 0 || idx >= len(m.stagedFiles) {
+		return nil
+	}
+	m.stagedFiles[idx].Pages = pages
+	if pages == "" {
+		m.setStatus("Printing all pages of " + m.stagedFiles[idx].Name)
+	} else {
+		m.setStatus(fmt.Sprintf("Printing pages %s of %s", pages, m.stagedFiles[idx].Name))
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func (m *model) renderQueueContent(width, height int) string {
@@ -12,25 +14,15 @@ func (m *model) renderQueueContent(width, height int) string {
 
 	var result strings.Builder
 
-	// Count active jobs (deduplicated)
-	totalJobs := len(m.jobs)
-	for _, op := range m.printOps {
-		hasSystemJob := false
-		if op.SystemJobID != "" {
-			for _, job := range m.jobs {
-				if job.ID == op.SystemJobID {
-					hasSystemJob = true
-					break
-				}
-			}
-		}
-		if !hasSystemJob && op.Status != StatusSent {
-			totalJobs++
-		}
+	// Count active jobs (deduplicated, or only problems when filtered)
+	totalJobs := m.getActualJobCount()
+
+	if config.Offline {
+		return m.renderOfflineQueue(width, height)
 	}
 
 	// Printer header with status
-	printer := getDefaultPrinter()
+	printer := m.currentPrinter()
 	printerName := printerNameStyle.Render(fmt.Sprintf("🖨  %s", printer.Name))
 	var statusStyled string
 	if printer.Status == "idle" || printer.Status == "" {
@@ -38,6 +30,13 @@ func (m *model) renderQueueContent(width, height int) string {
 	} else {
 		statusStyled = printerStatusActiveStyle.Render(fmt.Sprintf(" - %s", printer.Status))
 	}
+	if printer.Reason != "" {
+		// Keep the header on one line; I shows the full CUPS state
+		room := width - lipgloss.Width(printerName+statusStyled) - 3
+		if room > 3 {
+			statusStyled += printerStatusActiveStyle.Render(" (" + truncate(sanitizeForDisplay(printer.Reason), room) + ")")
+		}
+	}
 	result.WriteString(printerName + statusStyled)
 	result.WriteString("\n")
 
@@ -52,7 +51,6 @@ func (m *model) renderQueueContent(width, height int) string {
 
 	// Dynamic height allocation
 	var activeScrollHeight, stagedScrollHeight int
-	relativeStagedFiles := m.getRelativeStagedFiles()
 
 	if totalJobs == 0 {
 		activeScrollHeight = 1
@@ -61,7 +59,7 @@ func (m *model) renderQueueContent(width, height int) string {
 			stagedScrollHeight = 1
 		}
 	} else {
-		activeJobLines := totalJobs
+		activeJobLines := totalJobs + m.pinDividerLines(totalJobs)
 		maxActiveHeight := availableHeight / 2
 		if maxActiveHeight < 3 {
 			maxActiveHeight = 3
@@ -83,158 +81,198 @@ func (m *model) renderQueueContent(width, height int) string {
 	// Tree characters
 	treeBranch := treeStyle.Render("├─ ")
 	treeVert := treeStyle.Render("│")
-	treeLast := treeStyle.Render("└─ ")
 
 	// Active section header
 	activeHeader := fmt.Sprintf("📄 Active (%d)", totalJobs)
+	if m.problemsOnly {
+		activeHeader = fmt.Sprintf("📄 Failed/Canceled (%d)", totalJobs)
+	}
 	result.WriteString(treeBranch + activeHeaderStyle.Render(activeHeader))
 	result.WriteString("\n")
 
-	// Build active jobs content
-	printOpsByJobID := make(map[string]*PrintOperation)
-	for i := range m.printOps {
-		op := &m.printOps[i]
-		if op.SystemJobID != "" {
-			printOpsByJobID[op.SystemJobID] = op
-		}
-	}
-	shownOpIDs := make(map[string]bool)
+	// Pinned jobs are sorted first in m.jobs; a divider line separates them from the rest
+	pinnedCount := m.pinnedJobCount()
+	dividerLines := m.pinDividerLines(totalJobs)
 
 	var activeContent strings.Builder
-	if totalJobs == 0 {
+	var activeRows []int // Item index of each content line, for mouse clicks
+	expandedLines := 0   // Continuation lines of the expanded cursor row
+	if totalJobs == 0 && m.problemsOnly {
+		activeContent.WriteString(treeVert + dimStyle.Render("     · No failed or canceled jobs"))
+	} else if totalJobs == 0 {
 		activeContent.WriteString(treeVert + dimStyle.Render("     · No active jobs"))
-	} else {
-		itemIndex := 0
-
-		for _, job := range m.jobs {
-			isCursor := itemIndex == m.activeCursor && m.activePane == PaneQueue && m.queueSection == SectionActive
-
-			var statusSymbol string
-			var statusStyle = normalStyle
-			fileName := job.FileName
-
-			if op, exists := printOpsByJobID[job.ID]; exists {
-				shownOpIDs[op.ID] = true
-				switch op.Status {
-				case StatusPending:
-					statusSymbol = "⏳"
-					statusStyle = dimStyle
-				case StatusSending:
-					statusSymbol = "📤"
-					statusStyle = normalStyle
-				case StatusSent:
-					statusSymbol = "●"
-					statusStyle = normalStyle
-				case StatusFailed:
-					statusSymbol = "✗"
-					statusStyle = errorStyle
-				case StatusCanceled:
-					statusSymbol = "⊘"
-					statusStyle = dimStyle
-				}
+	}
+	for itemIndex, entry := range m.buildActiveJobList() {
+		isCursor := itemIndex == m.activeCursor && m.activePane == PaneQueue && m.queueSection == SectionActive
+
+		var statusSymbol, fileName, rowID string
+		statusStyle := normalStyle
+		if entry.Completed > 0 {
+			rowID = "completed"
+			statusSymbol, statusStyle = "✓", allDoneStyle
+			fileName = fmt.Sprintf("%d printed (C: list, x: clear)", entry.Completed)
+		} else if job := entry.Job; job != nil {
+			rowID = "job:" + job.ID
+			statusSymbol = "●"
+			fileName = job.FileName
+			if op := entry.Op; op != nil {
+				statusSymbol, statusStyle = opStatusSymbol(op.Status)
 				if op.FileName != "" {
 					fileName = op.FileName
 				}
-			} else {
-				statusSymbol = "●"
-			}
-
-			maxNameLen := width - 15
-			if maxNameLen > 0 && len(fileName) > maxNameLen {
-				fileName = fileName[:maxNameLen-3] + "..."
+				fileName += copiesSuffix(op.Copies)
 			}
-
-			content := fmt.Sprintf("%s %s", statusSymbol, fileName)
-			activeContent.WriteString(treeVert + renderSelectable(isCursor, 5, content, selectedFileStyle, statusStyle))
-
-			if itemIndex < totalJobs-1 {
-				activeContent.WriteString("\n")
+			if m.pinnedJobs[job.ID] {
+				statusSymbol = "📌"
 			}
-			itemIndex++
-		}
-
-		for _, op := range m.printOps {
-			if shownOpIDs[op.ID] {
-				continue
-			}
-			if op.SystemJobID != "" {
-				hasSystemJob := false
-				for _, job := range m.jobs {
-					if job.ID == op.SystemJobID {
-						hasSystemJob = true
-						break
-					}
-				}
-				if hasSystemJob {
-					continue
-				}
+		} else {
+			op := entry.Op
+			rowID = "op:" + op.ID
+			statusSymbol, statusStyle = opStatusSymbol(op.Status)
+			fileName = op.FileName + copiesSuffix(op.Copies)
+			if op.Status == StatusSafeMode {
+				fileName += " — SAFE MODE, not printed"
 			}
-			if op.Status == StatusSent || op.Status == StatusCanceled {
-				continue
+			if op.Command != "" {
+				fileName += " — dry run: " + op.Command
 			}
-
-			isCursor := itemIndex == m.activeCursor && m.activePane == PaneQueue && m.queueSection == SectionActive
-
-			var statusSymbol string
-			var statusStyle = normalStyle
-			switch op.Status {
-			case StatusPending:
-				statusSymbol = "⏳"
-				statusStyle = dimStyle
-			case StatusSending:
-				statusSymbol = "📤"
-				statusStyle = normalStyle
-			case StatusFailed:
-				statusSymbol = "✗"
-				statusStyle = errorStyle
+			// Expanded failed rows also show the full error
+			if op.Status == StatusFailed && op.Error != nil && m.expandedRow == rowID {
+				fileName += " — " + strings.TrimSpace(op.Error.Error())
 			}
+		}
 
-			fileName := op.FileName
-			maxNameLen := width - 15
-			if maxNameLen > 0 && len(fileName) > maxNameLen {
-				fileName = fileName[:maxNameLen-3] + "..."
-			}
+		maxNameLen := width - 15
+		fileName, more := m.fitRow(rowID, fileName, maxNameLen)
 
-			content := fmt.Sprintf("%s %s", statusSymbol, fileName)
-			activeContent.WriteString(treeVert + renderSelectable(isCursor, 5, content, selectedFileStyle, statusStyle))
+		content := fmt.Sprintf("%s %s", statusSymbol, fileName)
+		activeContent.WriteString(treeVert + renderSelectable(isCursor, 5, content, selectedFileStyle, statusStyle))
+		activeContent.WriteString(continuationLines(treeVert, 5+lipgloss.Width(statusSymbol+" "), more, statusStyle))
+		for range len(more) + 1 {
+			activeRows = append(activeRows, itemIndex)
+		}
+		if isCursor {
+			expandedLines = len(more)
+		}
 
-			if itemIndex < totalJobs-1 {
-				activeContent.WriteString("\n")
-			}
-			itemIndex++
+		if itemIndex < totalJobs-1 {
+			activeContent.WriteString("\n")
+		}
+		if dividerLines > 0 && itemIndex+1 == pinnedCount {
+			activeContent.WriteString(treeVert + dimStyle.Render("     "+strings.Repeat("┄", max(0, width-8))))
+			activeContent.WriteString("\n")
+			activeRows = append(activeRows, -1)
 		}
 	}
 
-	activeScroll := NewScrollableArea(width, activeScrollHeight)
+	activeScroll := m.newScrollArea(width, activeScrollHeight)
 	activeScroll.SetContent(activeContent.String())
 	if m.queueSection == SectionActive && totalJobs > 0 {
-		activeScroll.ScrollToLine(m.activeCursor)
+		cursorLine := m.activeCursor
+		if dividerLines > 0 && m.activeCursor >= pinnedCount {
+			cursorLine += dividerLines
+		}
+		activeScroll.ScrollToLine(cursorLine + expandedLines)
+		activeScroll.ScrollToLine(cursorLine)
 	}
+	m.mouse.add(TargetActive, 2, width, activeScrollHeight, activeScroll, activeRows)
 	result.WriteString(activeScroll.Render())
 	result.WriteString("\n")
 
-	// Staged section header
+	// Below the printer, active header and active list
+	result.WriteString(m.renderStagedSection(width, stagedScrollHeight, totalJobs, 2+activeScrollHeight))
+
+	return result.String()
+}
+
+// copiesSuffix is the " ×N" shown after a file name printed more than once
+func copiesSuffix(copies int) string {
+	if copies > 1 {
+		return fmt.Sprintf(" ×%d", copies)
+	}
+	return ""
+}
+
+// renderStagedSection renders the staged header and its scrollable file list,
+// starting at line top of the pane's content
+func (m *model) renderStagedSection(width, scrollHeight, totalJobs, top int) string {
+	var result strings.Builder
+	relativeStagedFiles := m.getRelativeStagedFiles()
+	treeLast := treeStyle.Render("└─ ")
+
+	// Staged section header, warning about files printed recently
+	recentlyPrinted := m.recentlyPrintedStaged()
 	stagedHeader := fmt.Sprintf("📋 Staged (%d)", len(relativeStagedFiles))
 	result.WriteString(treeLast + stagedHeaderStyle.Render(stagedHeader))
+	if len(recentlyPrinted) > 0 {
+		result.WriteString(emptyFileStyle.Render(fmt.Sprintf("  ↺ %d of %d printed in the last %s",
+			len(recentlyPrinted), len(relativeStagedFiles), formatWindow(config.RecentPrintWindow))))
+	}
 	result.WriteString("\n")
 
 	// Build staged files content
 	var stagedContent strings.Builder
-	if len(relativeStagedFiles) == 0 {
+	var stagedRows []int // Item index of each content line, for mouse clicks
+	expandedLines := 0   // Continuation lines of the expanded cursor row
+	if len(relativeStagedFiles) == 0 && totalJobs == 0 {
+		// Nothing queued anywhere: point new users at how to start
+		stagedContent.WriteString("\n")
+		stagedContent.WriteString(allDoneStyle.Render("      ✓ All done, nothing to print"))
+		stagedContent.WriteString("\n")
+		stagedContent.WriteString(dimStyle.Render("      Add files with a / f, then P to print"))
+	} else if len(relativeStagedFiles) == 0 {
 		stagedContent.WriteString(dimStyle.Render("      · No staged files"))
 	} else {
+		// Number entries of a file staged several times: each is its own job
+		jobTotals := make(map[string]int)
+		for _, file := range relativeStagedFiles {
+			jobTotals[file.Path]++
+		}
+		jobNumbers := make(map[string]int)
+
 		for i, file := range relativeStagedFiles {
 			isCursor := i == m.stagedCursor && m.activePane == PaneQueue && m.queueSection == SectionStaged
+			jobNumbers[file.Path]++
 
 			fileName := m.formatStagedFileName(file)
 			maxNameLen := width - 14 // Extra space for copy indicator
-			if maxNameLen > 0 && len(fileName) > maxNameLen {
-				fileName = fileName[:maxNameLen-3] + "..."
+			if file.Size == 0 {
+				maxNameLen -= len(emptySuffix)
+			}
+			fileName, more := m.fitRow("staged:"+file.Path, fileName, maxNameLen)
+			if isCursor {
+				expandedLines = len(more)
 			}
 
 			// Show ? for pending remove, ×N for multiple copies, ◉ for single
 			var indicator string
 			var style = printableStyle
+			if file.Size == 0 {
+				fileName += emptySuffix
+				style = emptyFileStyle
+			}
+			if total := jobTotals[file.Path]; total > 1 {
+				fileName += fmt.Sprintf(" [job %d/%d]", jobNumbers[file.Path], total)
+			}
+			if file.Pages != "" {
+				fileName += " [" + file.Pages + "]"
+			}
+			if file.Duplex != DuplexNone {
+				fileName += " ⇄"
+			}
+			switch file.Color {
+			case ColorGray:
+				fileName += " B/W"
+			case ColorColor:
+				fileName += " RGB"
+			}
+			if len(file.ExtraOptions) > 0 {
+				fileName += " ⚙"
+			}
+			if recentlyPrinted[file.Path] {
+				fileName += " ↺"
+				style = emptyFileStyle
+			}
 			if file.PendingRemove {
 				indicator = "?"
 				style = errorStyle
@@ -246,6 +284,13 @@ func (m *model) renderQueueContent(width, height int) string {
 
 			content := fmt.Sprintf("%s %s", indicator, fileName)
 			stagedContent.WriteString(renderSelectable(isCursor, 6, content, selectedFileStyle, style))
+			if file.Note != "" {
+				stagedContent.WriteString(dimStyle.Render("  " + truncate(file.Note, max(0, width-lipgloss.Width(content)-10))))
+			}
+			stagedContent.WriteString(continuationLines("", 6+lipgloss.Width(indicator+" "), more, style))
+			for range len(more) + 1 {
+				stagedRows = append(stagedRows, i)
+			}
 
 			if i < len(relativeStagedFiles)-1 {
 				stagedContent.WriteString("\n")
@@ -253,12 +298,30 @@ func (m *model) renderQueueContent(width, height int) string {
 		}
 	}
 
-	stagedScroll := NewScrollableArea(width, stagedScrollHeight)
+	stagedScroll := m.newScrollArea(width, scrollHeight)
 	stagedScroll.SetContent(stagedContent.String())
 	if m.queueSection == SectionStaged && len(relativeStagedFiles) > 0 {
+		stagedScroll.ScrollToLine(m.stagedCursor + expandedLines)
 		stagedScroll.ScrollToLine(m.stagedCursor)
 	}
+	m.mouse.add(TargetStaged, top+1, width, scrollHeight, stagedScroll, stagedRows)
 	result.WriteString(stagedScroll.Render())
 
 	return result.String()
 }
+
+// renderOfflineQueue renders the queue in offline mode: staging only, no active jobs
+func (m *model) renderOfflineQueue(width, height int) string {
+	var result strings.Builder
+	result.WriteString(printerNameStyle.Render("🖨  Offline"))
+	result.WriteString(printerStatusIdleStyle.Render(" - jobs not polled"))
+	result.WriteString("\n")
+
+	// Overhead: printer (1) + staged header (1)
+	scrollHeight := height - 2
+	if scrollHeight <= 0 {
+		return result.String()
+	}
+	result.WriteString(m.renderStagedSection(width, scrollHeight, 0, 1))
+	return result.String()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// reportHistoryLimit caps how many tracked jobs the report lists
+const reportHistoryLimit = 50
+
+// queueReport is a snapshot of everything the report documents
+type queueReport struct {
+	GeneratedAt time.Time
+	Jobs        []PrintJob       // Jobs currently in the system queue
+	Operations  []PrintOperation // Submissions made this session
+	Staged      []StagedFile
+	History     []JobInfo // Tracked jobs, newest first
+}
+
+// writeReport writes the snapshot as a markdown document
+func writeReport(w io.Writer, r queueReport) error {
+	var b strings.Builder
+	const stamp = "2006-01-02 15:04:05"
+
+	fmt.Fprintf(&b, "# Print report\n\nGenerated %s\n", r.GeneratedAt.Format(stamp))
+
+	b.WriteString("\n## Active jobs\n\n")
+	if len(r.Jobs) == 0 {
+		b.WriteString("No jobs in the system queue.\n")
+	}
+	for _, job := range r.Jobs {
+		fmt.Fprintf(&b, "- #%s %s (%s, %s)", job.ID, job.FileName, job.Owner, job.Status)
+		if job.Size >= 0 {
+			fmt.Fprintf(&b, ", %s", formatSize(job.Size))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Operations) > 0 {
+		b.WriteString("\n## Sent this session\n\n")
+		for _, op := range r.Operations {
+			fmt.Fprintf(&b, "- %s %s: %s", op.StartedAt.Format(stamp), op.FileName, op.statusLabel())
+			if op.SystemJobID != "" {
+				fmt.Fprintf(&b, " (job %s)", op.SystemJobID)
+			}
+			if op.Error != nil {
+				fmt.Fprintf(&b, " — %v", op.Error)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n## Staged files\n\n")
+	if len(r.Staged) == 0 {
+		b.WriteString("Nothing staged.\n")
+	}
+	for _, file := range r.Staged {
+		fmt.Fprintf(&b, "- %s (%s)", file.Path, formatSize(file.Size))
+		var opts []string
+		if file.Copies > 1 {
+			opts = append(opts, fmt.Sprintf("%d copies", file.Copies))
+		}
+		if file.Printer != "" {
+			opts = append(opts, "printer "+file.Printer)
+		}
+		if file.Duplex != DuplexNone {
+			opts = append(opts, file.Duplex.String())
+		}
+		if file.Color != ColorAuto {
+			opts = append(opts, file.Color.String())
+		}
+		if file.Pages != "" {
+			opts = append(opts, "pages "+file.Pages)
+		}
+		opts = append(opts, file.ExtraOptions...)
+		if len(opts) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(opts, ", "))
+		}
+		if file.Note != "" {
+			fmt.Fprintf(&b, " — %s", file.Note)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Recent history\n\n")
+	if len(r.History) == 0 {
+		b.WriteString("No recorded jobs.\n")
+	}
+	for _, info := range r.History {
+		fmt.Fprintf(&b, "- %s #%s %s", info.SubmittedAt.Format(stamp), info.JobID, info.FilePath)
+		if info.Copies > 1 {
+			fmt.Fprintf(&b, " ×%d", info.Copies)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// runReport writes a report of the system queue and job history to path
+// ("-" for stdout). Nothing is staged outside the TUI, so that section is empty.
+func runReport(path string) error {
+	jobs, err := backend.ListJobs()
+	if err != nil {
+		return err
+	}
+	tracker, _ := NewJobTracker()
+	r := queueReport{
+		GeneratedAt: time.Now(),
+		Jobs:        jobs,
+		History:     tracker.Recent(reportHistoryLimit),
+	}
+	if path == "-" {
+		return writeReport(os.Stdout, r)
+	}
+	return writeReportFile(path, r)
+}
+
+// writeReportFile writes the report to path, replacing any existing file
+func writeReportFile(path string, r queueReport) error {
+	var b strings.Builder
+	if err := writeReport(&b, r); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// exportReport writes the current queue, staging and history to a
+// timestamped markdown file in the browsed directory
+func (m *model) exportReport() {
+	now := time.Now()
+	r := queueReport{
+		GeneratedAt: now,
+		Jobs:        m.jobs,
+		Operations:  m.printOps,
+		Staged:      m.stagedFiles,
+		History:     m.tracker.Recent(reportHistoryLimit),
+	}
+	path := filepath.Join(m.currentDir, "print-report-"+now.Format("20060102-150405")+".md")
+	if err := writeReportFile(path, r); err != nil {
+		m.setError("Could not write report: " + err.Error())
+		return
+	}
+	m.setStatus("Report written to " + path)
+}
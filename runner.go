@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+)
+
+// commandRunner runs the print system's command line tools. The print path
+// goes through runner rather than os/exec, so it can be exercised with a
+// fake that records argv and returns canned output instead of needing CUPS.
+type commandRunner interface {
+	// Run runs a command to completion and returns what it wrote to stdout
+	// and stderr; err is non-nil when it couldn't start or exited non-zero
+	Run(ctx context.Context, name string, args ...string) (stdout, stderr []byte, err error)
+	// Output runs a command and returns its stdout, like exec.Cmd.Output
+	Output(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// runner is the command runner in use; only tests replace it
+var runner commandRunner = execRunner{}
+
+// execRunner runs commands with os/exec
+type execRunner struct{}
+
+func (execRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+func (execRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
@@ -12,6 +12,7 @@ type ScrollableArea struct {
 	height       int      // Visible height
 	scrollOffset int      // Current scroll position
 	showScrollbar bool    // Whether content needs scrollbar
+	scrollbarOff  bool    // Never draw the scrollbar, even on overflow
 }
 
 // NewScrollableArea creates a new scrollable area
@@ -27,7 +28,7 @@ func NewScrollableArea(width, height int) *ScrollableArea {
 // SetContent sets the content and determines if scrollbar is needed
 func (s *ScrollableArea) SetContent(content string) {
 	s.content = strings.Split(content, "\n")
-	s.showScrollbar = len(s.content) > s.height
+	s.showScrollbar = !s.scrollbarOff && len(s.content) > s.height
 	
 	// Reset scroll if content changed significantly
 	if s.scrollOffset >= len(s.content) {
@@ -35,6 +36,15 @@ func (s *ScrollableArea) SetContent(content string) {
 	}
 }
 
+// SetScrollbarEnabled toggles scrollbar rendering; when disabled the
+// content gets the full width. Call before SetContent.
+func (s *ScrollableArea) SetScrollbarEnabled(enabled bool) {
+	s.scrollbarOff = !enabled
+	if s.scrollbarOff {
+		s.showScrollbar = false
+	}
+}
+
 // ScrollUp scrolls the content up by n lines
 func (s *ScrollableArea) ScrollUp(n int) {
 	s.scrollOffset -= n
@@ -109,6 +119,7 @@ func (s *ScrollableArea) Render() string {
 	if s.showScrollbar {
 		contentWidth = s.width - 2 // Reserve 2 chars for scrollbar
 	}
+	contentWidth = max(0, contentWidth)
 	
 	// Build the visible content
 	var result strings.Builder
@@ -137,7 +148,7 @@ func (s *ScrollableArea) Render() string {
 				line := strings.Repeat(" ", contentWidth) + s.getScrollbarChar(i)
 				result.WriteString(line)
 			} else {
-				result.WriteString(strings.Repeat(" ", s.width))
+				result.WriteString(strings.Repeat(" ", max(0, s.width)))
 			}
 		}
 		
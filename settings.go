@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Settings are the choices carried over to the next run: the selected
+// printer and the copies and duplex mode newly staged files start from.
+// Persisted as settings.json beside jobs.json in the data dir.
+type Settings struct {
+	Printer string     `json:"printer,omitempty"` // "" follows the system default
+	Copies  int        `json:"copies,omitempty"`
+	Duplex  DuplexMode `json:"duplex,omitempty"`
+}
+
+// settingsPath returns where the settings are kept between runs
+func settingsPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.json"), nil
+}
+
+// loadSettings reads the settings saved by a previous run
+func loadSettings() Settings {
+	var s Settings
+	path, err := settingsPath()
+	if err != nil {
+		return s
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		// A corrupt file just starts over
+		if json.Unmarshal(data, &s) != nil {
+			s = Settings{}
+		}
+	}
+	return s
+}
+
+// saveSettings writes the settings atomically
+func saveSettings(s Settings) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := ensureWritableDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// rememberSettings saves the settings when they changed. Failures are silent:
+// the choices still apply to this run, like everything in memory-only mode.
+func (m *model) rememberSettings(s Settings) {
+	if s == m.settings {
+		return
+	}
+	m.settings = s
+	_ = saveSettings(s)
+}
+
+// rememberPrintSettings keeps the copies and duplex mode of a sent job as
+// the starting point for files staged later
+func (m *model) rememberPrintSettings(op PrintOperation) {
+	s := m.settings
+	s.Copies = op.Copies
+	s.Duplex = op.Duplex
+	m.rememberSettings(s)
+}
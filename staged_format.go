@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultStagedFormat shows each staged file by its path relative to the
+// browsed directory
+const defaultStagedFormat = "{relpath}"
+
+// stagedPlaceholders are the fields a staged_format template can use
+var stagedPlaceholders = map[string]bool{
+	"name":    true, // File name
+	"relpath": true, // Path relative to the browsed directory
+	"reldir":  true, // Directory relative to the browsed directory
+	"path":    true, // Absolute path
+	"size":    true, // Human-readable size
+	"copies":  true, // Number of copies
+	"options": true, // Extra CUPS options, space separated
+	"pages":   true, // Page range, empty for all pages
+	"printer": true, // Destination, empty for the selected printer
+}
+
+// parseStagedFormat unquotes and validates a staged_format template: braces
+// must be balanced and every {placeholder} known
+func parseStagedFormat(raw string) (string, error) {
+	format, err := strconv.Unquote(raw)
+	if err != nil {
+		return "", err
+	}
+	rest := format
+	for {
+		open := strings.IndexAny(rest, "{}")
+		if open == -1 {
+			break
+		}
+		if rest[open] == '}' {
+			return "", fmt.Errorf("unmatched } in %q", format)
+		}
+		end := strings.Index(rest[open:], "}")
+		if end == -1 {
+			return "", fmt.Errorf("unclosed { in %q", format)
+		}
+		if name := rest[open+1 : open+end]; !stagedPlaceholders[name] {
+			return "", fmt.Errorf("unknown placeholder {%s}", name)
+		}
+		rest = rest[open+end+1:]
+	}
+	if strings.TrimSpace(format) == "" {
+		return "", fmt.Errorf("format is empty")
+	}
+	return format, nil
+}
+
+// formatStagedRow fills a validated template with a staged file's fields
+func (m model) formatStagedRow(format string, file StagedFile) string {
+	relPath, err := filepath.Rel(m.currentDir, file.Path)
+	if err != nil {
+		// If we can't get relative path, show full path
+		relPath = file.Path
+	}
+	relDir := filepath.Dir(relPath)
+	if relDir == "." {
+		relDir = ""
+	} else {
+		relDir += string(filepath.Separator)
+	}
+
+	return strings.NewReplacer(
+		"{name}", file.Name,
+		"{relpath}", relPath,
+		"{reldir}", relDir,
+		"{path}", file.Path,
+		"{size}", formatSize(file.Size),
+		"{copies}", strconv.Itoa(file.Copies),
+		"{options}", strings.Join(file.ExtraOptions, " "),
+		"{pages}", file.Pages,
+		"{printer}", file.Printer,
+	).Replace(format)
+}
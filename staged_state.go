@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stagedStatePath returns where the staged list is kept between sessions
+// (staged.json in the data dir)
+func stagedStatePath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "staged.json"), nil
+}
+
+// loadStagedState reads the staged list saved by the previous session.
+// Files that were moved or deleted since are dropped and counted in missing.
+func loadStagedState() (files []StagedFile, missing int) {
+	path, err := stagedStatePath()
+	if err != nil {
+		return nil, 0
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0
+	}
+	var saved []StagedFile
+	if json.Unmarshal(data, &saved) != nil {
+		// A corrupt file just starts with nothing staged
+		return nil, 0
+	}
+
+	for _, file := range saved {
+		info, err := os.Stat(file.Path)
+		if err != nil || info.IsDir() {
+			missing++
+			continue
+		}
+		file.Size = info.Size()
+		file.PendingRemove = false
+		files = append(files, file)
+	}
+	return files, missing
+}
+
+// saveStagedState writes the staged list atomically; an empty list removes the file
+func saveStagedState(files []StagedFile) error {
+	path, err := stagedStatePath()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := ensureWritableDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// restoreStagedState stages the files left from the previous session
+func (m *model) restoreStagedState() {
+	files, missing := loadStagedState()
+	for _, file := range files {
+		m.stagedFiles = append(m.stagedFiles, file)
+		m.markedFiles[file.Path] = true
+	}
+	m.savedStaged = append([]StagedFile(nil), m.stagedFiles...)
+
+	switch {
+	case len(files) > 0 && missing > 0:
+		m.setStatus(fmt.Sprintf("Restored %d staged file(s); %d no longer exist", len(files), missing))
+	case len(files) > 0:
+		m.setStatus(fmt.Sprintf("Restored %d staged file(s) from the last session", len(files)))
+	}
+}
+
+// stagedSaveInterval is the most often edits to the staged list are written;
+// a crash or kill loses at most this much staging work
+const stagedSaveInterval = 2 * time.Second
+
+// syncStagedState saves the staged list once it differs from what was last
+// written, at most every stagedSaveInterval. It runs after every update, so
+// the regular ticks flush pending edits without any extra timer.
+func (m *model) syncStagedState() {
+	if time.Since(m.stagedSavedAt) < stagedSaveInterval {
+		return
+	}
+	m.flushStagedState()
+}
+
+// flushStagedState writes pending staged edits right away
+func (m *model) flushStagedState() error {
+	if !config.PersistStaging {
+		return nil
+	}
+	if (len(m.stagedFiles) == 0 && len(m.savedStaged) == 0) || reflect.DeepEqual(m.stagedFiles, m.savedStaged) {
+		return nil
+	}
+	m.stagedSavedAt = time.Now()
+	if err := saveStagedState(m.stagedFiles); err != nil {
+		if !m.stagedSaveFailed {
+			// Report once; the staged list keeps working in memory
+			m.setError("Cannot save staged files: " + err.Error())
+			m.stagedSaveFailed = true
+		}
+		return err
+	}
+	m.stagedSaveFailed = false
+	m.savedStaged = append([]StagedFile(nil), m.stagedFiles...)
+	return nil
+}
+
+// requestQuit quits, first warning when staged files would be lost because
+// staging isn't kept between sessions
+func (m *model) requestQuit() tea.Cmd {
+	if config.PersistStaging || len(m.stagedFiles) == 0 {
+		return tea.Quit
+	}
+	m.openConfirm(ConfirmQuit, fmt.Sprintf(
+		"%d staged file(s) will be lost (persist_staging is off).\nQuit anyway?",
+		len(m.stagedFiles)))
+	return nil
+}
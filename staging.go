@@ -0,0 +1,437 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxBatchSets caps how many complete sets of the batch can be printed at once
+const maxBatchSets = 99
+
+// requestPrintStaged starts printing the staged files, first asking for
+// confirmation when some of them were already printed recently.
+// smallestFirst submits them one by one in ascending size order.
+func (m *model) requestPrintStaged(smallestFirst bool) tea.Cmd {
+	if len(m.stagedFiles) == 0 {
+		return nil
+	}
+	m.printSmallestFirst = smallestFirst
+	m.printSets = 1
+
+	if sending := m.stagedInFlight(); sending > 0 {
+		// Likely a double submission; y prints them again on purpose
+		m.openConfirm(ConfirmPrintStaged, fmt.Sprintf(
+			"%d of %d staged file(s) are still being sent.\nSubmit them again?",
+			sending, len(m.stagedFiles)))
+		return nil
+	}
+	if dupes := m.recentlyPrintedStaged(); len(dupes) > 0 {
+		m.openConfirm(ConfirmPrintStaged, fmt.Sprintf(
+			"%d of %d staged file(s) were printed in the last %s.\nPrint anyway? (d: deselect them)",
+			len(dupes), len(m.stagedFiles), formatWindow(config.RecentPrintWindow)))
+		return nil
+	}
+	if reason := m.largeBatchReason(); reason != "" {
+		m.openConfirm(ConfirmPrintStaged, fmt.Sprintf("%s\nPrint %d file(s) to %s?",
+			reason, len(m.stagedFiles), m.batchDestination()))
+		return nil
+	}
+	return m.printStaged()
+}
+
+// largeBatchReason describes why the staged batch is over the configured
+// confirmation thresholds, or returns "" when it can print straight away
+func (m model) largeBatchReason() string {
+	if config.ConfirmOverFiles > 0 && len(m.stagedFiles) > config.ConfirmOverFiles {
+		return fmt.Sprintf("This batch has %d files (more than %d).", len(m.stagedFiles), config.ConfirmOverFiles)
+	}
+	if config.ConfirmOverSize > 0 {
+		var total int64
+		for _, file := range m.stagedFiles {
+			total += file.Size
+		}
+		if total > config.ConfirmOverSize {
+			return fmt.Sprintf("This batch is %s (more than %s).", formatSize(total), formatSize(config.ConfirmOverSize))
+		}
+	}
+	return ""
+}
+
+// batchDestination names where the staged batch goes: its printer, or how
+// many printers when files have their own
+func (m model) batchDestination() string {
+	printers := make(map[string]bool)
+	for _, file := range m.stagedFiles {
+		printers[m.destination(file.Printer)] = true
+	}
+	if len(printers) > 1 {
+		return fmt.Sprintf("%d printers", len(printers))
+	}
+	for printer := range printers {
+		if printer != "" {
+			return printer
+		}
+	}
+	return m.currentPrinter().Name
+}
+
+// requestPrintSets asks to confirm printing the whole staged batch sets times,
+// one complete set after another
+func (m *model) requestPrintSets(sets int) {
+	m.printSmallestFirst = false
+	m.printSets = sets
+
+	question := fmt.Sprintf("Print %d × %d file(s) (%d sets of the batch)?",
+		sets, len(m.stagedFiles), sets)
+	if dupes := m.recentlyPrintedStaged(); len(dupes) > 0 {
+		question += fmt.Sprintf("\n%d file(s) were printed in the last %s. (d: deselect them)",
+			len(dupes), formatWindow(config.RecentPrintWindow))
+	}
+	m.openConfirm(ConfirmPrintStaged, question)
+}
+
+// openBatchSetsPrompt asks how many complete sets of the staged batch to print
+func (m *model) openBatchSetsPrompt() tea.Cmd {
+	if len(m.stagedFiles) == 0 {
+		return nil
+	}
+	return m.openPrompt(PromptBatchSets, "Print Sets of Batch", "number of sets", "2")
+}
+
+// parseSets validates the batch sets prompt value
+func parseSets(value string) (int, error) {
+	sets, err := strconv.Atoi(value)
+	if err != nil || sets < 1 || sets > maxBatchSets {
+		return 0, fmt.Errorf("enter a number from 1 to %d", maxBatchSets)
+	}
+	return sets, nil
+}
+
+// openSplitJobsPrompt asks how many separate jobs to stage for the file
+// under the staged cursor
+func (m *model) openSplitJobsPrompt() tea.Cmd {
+	idx := m.stagedIndexAtCursor()
+	if idx == -1 {
+		return nil
+	}
+	m.promptIndex = idx
+	return m.openPrompt(PromptSplitJobs, "Separate Jobs: "+m.stagedFiles[idx].Name,
+		"number of jobs (each printed and canceled on its own)", "2")
+}
+
+// splitStagedJobs stages the entry at idx as n separate jobs by inserting
+// n-1 identical entries right after it. Unlike copies, each entry becomes
+// its own print job.
+func (m *model) splitStagedJobs(idx, n int) {
+	if idx < 0 || idx >= len(m.stagedFiles) {
+		return
+	}
+	file := m.stagedFiles[idx]
+	file.PendingRemove = false
+	extra := make([]StagedFile, n-1)
+	for i := range extra {
+		extra[i] = file
+		extra[i].ExtraOptions = append([]string(nil), file.ExtraOptions...)
+	}
+	rest := append(extra, m.stagedFiles[idx+1:]...)
+	m.stagedFiles = append(m.stagedFiles[:idx+1], rest...)
+	m.setStatus(fmt.Sprintf("%s staged as %d separate jobs", file.Name, m.stagedCount(file.Path)))
+}
+
+// stagedCount returns how many staged entries print path
+func (m model) stagedCount(path string) int {
+	count := 0
+	for _, file := range m.stagedFiles {
+		if file.Path == path {
+			count++
+		}
+	}
+	return count
+}
+
+// openDuplicateBatchPicker asks which printer should get a second set of the staged batch
+func (m *model) openDuplicateBatchPicker() tea.Cmd {
+	if len(m.stagedFiles) == 0 {
+		m.setStatus("Nothing staged to duplicate")
+		return nil
+	}
+	return m.openPrinterPicker(PickDuplicateBatch)
+}
+
+// duplicateBatchTo stages a copy of every staged entry targeted at printer,
+// keeping each entry's copies and options unless printer has remembered ones
+func (m *model) duplicateBatchTo(printer string) {
+	batch := len(m.stagedFiles)
+	for _, file := range m.stagedFiles[:batch] {
+		clone := file
+		clone.Printer = printer
+		clone.PendingRemove = false
+		clone.ExtraOptions = append([]string(nil), file.ExtraOptions...)
+		m.applyPrinterDefaults(&clone)
+		m.stagedFiles = append(m.stagedFiles, clone)
+	}
+	m.setStatus(fmt.Sprintf("Staged %d file(s) again for %s", batch, printer))
+}
+
+// cycleDuplex steps the staged file under the cursor to the next duplex mode
+func (m *model) cycleDuplex() {
+	idx := m.stagedIndexAtCursor()
+	if idx == -1 {
+		return
+	}
+	file := &m.stagedFiles[idx]
+	file.Duplex = (file.Duplex + 1) % 3
+	m.setStatus(fmt.Sprintf("%s: %s", file.Name, file.Duplex))
+}
+
+// cycleColor steps the staged file under the cursor through printer
+// default → grayscale → color
+func (m *model) cycleColor() {
+	idx := m.stagedIndexAtCursor()
+	if idx == -1 {
+		return
+	}
+	file := &m.stagedFiles[idx]
+	switch file.Color {
+	case ColorAuto:
+		file.Color = ColorGray
+	case ColorGray:
+		file.Color = ColorColor
+	default:
+		file.Color = ColorAuto
+	}
+	m.setStatus(fmt.Sprintf("%s: %s", file.Name, file.Color))
+}
+
+// removeStagedAt removes one staged entry, unmarking its file once no other
+// entry for the same path is left
+func (m *model) removeStagedAt(idx int) {
+	if idx < 0 || idx >= len(m.stagedFiles) {
+		return
+	}
+	path := m.stagedFiles[idx].Path
+	m.stagedFiles = append(m.stagedFiles[:idx], m.stagedFiles[idx+1:]...)
+	if m.stagedCount(path) == 0 {
+		delete(m.markedFiles, path)
+	}
+}
+
+// unstagePath unmarks a file and removes every staged entry for it
+func (m *model) unstagePath(path string) {
+	delete(m.markedFiles, path)
+	kept := m.stagedFiles[:0]
+	for _, file := range m.stagedFiles {
+		if file.Path != path {
+			kept = append(kept, file)
+		}
+	}
+	m.stagedFiles = kept
+}
+
+// printStaged sends all staged files to the printer and clears staging
+func (m *model) printStaged() tea.Cmd {
+	if len(m.stagedFiles) == 0 {
+		return nil
+	}
+
+	// Store start index before adding new operations
+	startIndex := len(m.printOps)
+
+	files := m.stagedFiles
+	if m.printSmallestFirst {
+		// Sort a copy so the staged display order is left alone
+		files = append([]StagedFile(nil), m.stagedFiles...)
+		sort.SliceStable(files, func(i, j int) bool {
+			return files[i].Size < files[j].Size
+		})
+	}
+
+	sets := m.printSets
+	if sets < 1 {
+		sets = 1
+	}
+
+	// Create print operations and commands for each staged file, once per set
+	var printCmds []tea.Cmd
+	emptyCount := 0
+	for set := 0; set < sets; set++ {
+		for i, file := range files {
+			if file.Size == 0 {
+				if set == 0 {
+					emptyCount++
+				}
+				if config.SkipEmptyFiles {
+					delete(m.markedFiles, file.Path)
+					continue
+				}
+			}
+			copies := file.Copies
+			if copies < 1 {
+				copies = 1
+			}
+			printer := m.destination(file.Printer)
+			// The entry index keeps IDs unique when a file is staged as several jobs
+			opID := fmt.Sprintf("%s-%d-%d-%d", file.Path, set, i, time.Now().UnixNano())
+			op := PrintOperation{
+				ID:        opID,
+				FilePath:  file.Path,
+				FileName:  file.Name,
+				Printer:   printer,
+				Copies:    copies,
+				Duplex:    file.Duplex,
+				Color:     file.Color,
+				Pages:     file.Pages,
+				Extra:     file.ExtraOptions,
+				Status:    StatusSending, // Start as sending since we submit immediately
+				StartedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}
+			m.printOps = append(m.printOps, op)
+
+			// Submit the print job - it runs async in its own goroutine
+			printCmds = append(printCmds, m.trackCmd(submitPrintJobCmd(opID, file.Path, PrintOptions{Printer: printer, Copies: copies, Duplex: file.Duplex, Color: file.Color, Pages: file.Pages, Extra: file.ExtraOptions})))
+
+			delete(m.markedFiles, file.Path)
+		}
+	}
+
+	// Snapshot the batch so it can be repeated later
+	m.lastBatch = append([]StagedFile(nil), m.stagedFiles...)
+
+	// Clear staged files
+	m.stagedFiles = []StagedFile{}
+	m.stagedCursor = 0
+
+	// Switch to queue pane to show progress
+	m.activePane = PaneQueue
+	m.queueSection = SectionActive // Focus on the active jobs section
+	// Position cursor at the first newly added operation
+	if startIndex < len(m.printOps) {
+		if row := m.activeIndexOfOp(m.printOps[startIndex].ID); row != -1 {
+			m.activeCursor = row
+		}
+	}
+
+	if emptyCount > 0 {
+		if config.SkipEmptyFiles {
+			m.setStatus(fmt.Sprintf("Skipped %d empty file(s)", emptyCount))
+		} else {
+			m.setError(fmt.Sprintf("Warning: %d empty file(s) sent to the printer", emptyCount))
+		}
+	}
+
+	if m.printSmallestFirst || sets > 1 {
+		// Sequence so the spooler receives them in size order / set by set
+		return tea.Sequence(printCmds...)
+	}
+	// Use Batch to run all commands concurrently
+	return tea.Batch(printCmds...)
+}
+
+// restageLastBatch stages the files and options of the most recent batch
+// again, reporting files that have since been moved or deleted
+func (m *model) restageLastBatch() {
+	if len(m.lastBatch) == 0 {
+		m.setStatus("No batch printed yet this session")
+		return
+	}
+
+	// Files staged before restaging are skipped; repeated entries of the
+	// batch (separate jobs of one file) are all restaged
+	alreadyStaged := make(map[string]bool, len(m.markedFiles))
+	for path := range m.markedFiles {
+		alreadyStaged[path] = true
+	}
+
+	var missing []string
+	restaged := 0
+	for _, file := range m.lastBatch {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			missing = append(missing, file.Name)
+			continue
+		}
+		if alreadyStaged[file.Path] {
+			continue // Already staged again
+		}
+		file.Size = info.Size()
+		file.AddedAt = time.Now()
+		file.PendingRemove = false
+		m.stagedFiles = append(m.stagedFiles, file)
+		m.markedFiles[file.Path] = true
+		restaged++
+	}
+
+	if len(missing) > 0 {
+		m.setError(fmt.Sprintf("Restaged %d file(s); missing: %s", restaged, strings.Join(missing, ", ")))
+		return
+	}
+	m.setStatus(fmt.Sprintf("Restaged %d file(s) from the last batch, P to print", restaged))
+}
+
+// stagedInFlight counts staged files that already have an operation still
+// pending or sending
+func (m model) stagedInFlight() int {
+	sending := make(map[string]bool)
+	for _, op := range m.printOps {
+		if !op.isTerminal() {
+			sending[op.FilePath] = true
+		}
+	}
+	count := 0
+	for _, file := range m.stagedFiles {
+		if sending[file.Path] {
+			count++
+		}
+	}
+	return count
+}
+
+// recentlyPrintedStaged returns the paths of staged files the tracker saw
+// printed within the configured window
+func (m model) recentlyPrintedStaged() map[string]bool {
+	dupes := make(map[string]bool)
+	if config.RecentPrintWindow <= 0 {
+		return dupes
+	}
+	for _, file := range m.stagedFiles {
+		if last, ok := m.tracker.LastPrinted(file.Path); ok && time.Since(last) < config.RecentPrintWindow {
+			dupes[file.Path] = true
+		}
+	}
+	return dupes
+}
+
+// deselectRecentlyPrinted unstages files that were printed recently
+func (m *model) deselectRecentlyPrinted() int {
+	dupes := m.recentlyPrintedStaged()
+	kept := m.stagedFiles[:0]
+	for _, file := range m.stagedFiles {
+		if dupes[file.Path] {
+			delete(m.markedFiles, file.Path)
+			continue
+		}
+		kept = append(kept, file)
+	}
+	m.stagedFiles = kept
+	m.clampCursors()
+	return len(dupes)
+}
+
+// formatWindow renders a duration like "hour", "30 minutes", "2 hours"
+func formatWindow(d time.Duration) string {
+	switch {
+	case d == time.Hour:
+		return "hour"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d minutes", int(d.Minutes()))
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stageTwo returns a test model with two files staged
+func stageTwo(t *testing.T) model {
+	t.Helper()
+	m := newTestModel(t)
+	m.stagedFiles = []StagedFile{
+		{Name: "a.pdf", Path: "/tmp/a.pdf", Copies: 1},
+		{Name: "b.pdf", Path: "/tmp/b.pdf", Copies: 1},
+	}
+	m.activePane = PaneQueue
+	m.queueSection = SectionStaged
+	return m
+}
+
+func TestPrintStagedSkipsSetsPrompt(t *testing.T) {
+	m := stageTwo(t)
+	m, _ = pressKey(t, m, "P")
+	if m.overlay == OverlayPrompt {
+		t.Fatalf("P opened prompt %d, want printing or its confirmation", m.promptAction)
+	}
+	if m.overlay != OverlayConfirm && len(m.printOps) != 2 {
+		t.Errorf("P queued %d operations, want 2", len(m.printOps))
+	}
+}
+
+func TestPrintSetsPrompt(t *testing.T) {
+	m := stageTwo(t)
+	m, _ = pressKey(t, m, "b")
+	if m.overlay != OverlayPrompt || m.promptAction != PromptBatchSets {
+		t.Fatalf("b opened overlay %d, want the sets prompt", m.overlay)
+	}
+	if got := m.promptInput.Value(); got != "2" {
+		t.Errorf("sets prompt prefilled %q, want %q", got, "2")
+	}
+
+	m.promptInput.SetValue("3")
+	updated, _ := m.update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.overlay != OverlayConfirm || m.confirmAction != ConfirmPrintStaged {
+		t.Fatalf("3 sets opened overlay %d, want the print confirmation", m.overlay)
+	}
+	if !strings.Contains(m.confirmText, "3 sets") || m.printSets != 3 {
+		t.Errorf("confirmation %q for %d sets, want 3 sets", m.confirmText, m.printSets)
+	}
+}
+
+func TestPrintSetsOneSetPrintsStraightAway(t *testing.T) {
+	m := stageTwo(t)
+	m, _ = pressKey(t, m, "b")
+	m.promptInput.SetValue("1")
+
+	updated, cmd := m.update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.overlay != OverlayNone {
+		t.Fatalf("one set left overlay %d open", m.overlay)
+	}
+	if cmd == nil || len(m.printOps) != 2 {
+		t.Errorf("one set queued %d operations, want 2", len(m.printOps))
+	}
+}
+
+func TestPrintSetsRejectsBadSets(t *testing.T) {
+	m := stageTwo(t)
+	m, _ = pressKey(t, m, "b")
+
+	m.promptInput.SetValue("0")
+	updated, _ := m.update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.overlay != OverlayPrompt || m.promptErr == "" {
+		t.Errorf("0 sets accepted: overlay %d, error %q", m.overlay, m.promptErr)
+	}
+}
@@ -1,14 +1,15 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,13 +20,29 @@ type tickMsg time.Time
 
 // jobsRefreshedMsg contains the refreshed list of print jobs
 type jobsRefreshedMsg struct {
-	jobs []PrintJob
+	jobs   []PrintJob
+	err    error // The queue couldn't be read; jobs is empty and means nothing
+	polled bool  // From the periodic tick, not counted as in-flight activity
+}
+
+// jobCanceledMsg reports the result of canceling a system job
+type jobCanceledMsg struct {
+	jobID string
+	opID  string // Operation the job belongs to, "" for jobs submitted elsewhere
+	err   error
+}
+
+// printersRefreshedMsg contains the refreshed list of available printers
+type printersRefreshedMsg struct {
+	printers []PrinterInfo
 }
 
 // PrinterInfo holds the default printer name and status
 type PrinterInfo struct {
-	Name   string
-	Status string // "idle", "printing", etc.
+	Name      string
+	Status    string // "idle", "printing", etc.
+	Reason    string // CUPS state message, e.g. "Media jam" ("" when none)
+	IsDefault bool   // Whether this is the system default destination
 }
 
 // getDefaultPrinter returns info about the default printer
@@ -33,30 +50,88 @@ func getDefaultPrinter() PrinterInfo {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "lpstat", "-p", "-d")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, "lpstat", "-p", "-d")
 	if err != nil {
 		return PrinterInfo{Name: "Unknown", Status: ""}
 	}
 
-	info := PrinterInfo{}
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "printer ") {
-			// "printer EPSON_ET_2810_Series is idle.  enabled since..."
-			parts := strings.Fields(line)
-			if len(parts) >= 4 {
-				info.Name = parts[1]
-				info.Status = parts[3]
-				// Remove trailing period
-				info.Status = strings.TrimSuffix(info.Status, ".")
+	printers := parsePrinterList(string(output))
+	if len(printers) == 0 {
+		return PrinterInfo{Name: "No printer"}
+	}
+	for _, p := range printers {
+		if p.IsDefault {
+			return p
+		}
+	}
+	return printers[len(printers)-1]
+}
+
+// getAvailablePrinters returns every printer known to CUPS with its state
+func getAvailablePrinters() []PrinterInfo {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	output, err := runner.Output(ctx, "lpstat", "-p", "-d")
+	if err != nil {
+		return []PrinterInfo{}
+	}
+
+	return parsePrinterList(string(output))
+}
+
+// parsePrinterList parses `lpstat -p -d` output into printers
+// "printer EPSON_ET_2810_Series is idle.  enabled since ..."
+// "printer Office disabled since ..."
+// "\tMedia jam" (the state message, indented under its printer)
+// "system default destination: EPSON_ET_2810_Series"
+func parsePrinterList(output string) []PrinterInfo {
+	var printers []PrinterInfo
+	defaultName := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") || strings.HasPrefix(line, " ") {
+			if n := len(printers); n > 0 && printers[n-1].Reason == "" {
+				printers[n-1].Reason = strings.TrimSpace(line)
 			}
+			continue
+		}
+		if strings.HasPrefix(line, "system default destination: ") {
+			defaultName = strings.TrimSpace(strings.TrimPrefix(line, "system default destination: "))
+			continue
+		}
+		if !strings.HasPrefix(line, "printer ") {
+			continue
+		}
+
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			continue
 		}
+		info := PrinterInfo{Name: parts[1]}
+		if parts[2] == "is" && len(parts) >= 4 {
+			info.Status = strings.TrimSuffix(parts[3], ".")
+		} else {
+			info.Status = strings.TrimSuffix(parts[2], ".")
+		}
+		printers = append(printers, info)
+	}
+
+	// $LPDEST/$PRINTER/lpoptions pick the user's default over the system one
+	if userDefault := cupsUserDefault(); userDefault != "" {
+		defaultName = userDefault
+	}
+	for i := range printers {
+		printers[i].IsDefault = printers[i].Name == queueName(defaultName)
 	}
-	if info.Name == "" {
-		info.Name = "No printer"
+	return printers
+}
+
+// refreshPrintersCmd rediscovers printers asynchronously (e.g. a USB printer plugged in mid-session)
+func refreshPrintersCmd() tea.Cmd {
+	return func() tea.Msg {
+		return printersRefreshedMsg{printers: backend.ListPrinters()}
 	}
-	return info
 }
 
 // tickCmd returns a command that sends a tickMsg every second
@@ -70,107 +145,254 @@ func tickCmd() tea.Cmd {
 func refreshJobsCmd() tea.Cmd {
 	return func() tea.Msg {
 		// This runs in a background goroutine, not blocking the UI
-		jobs := getSystemPrintJobs()
-		return jobsRefreshedMsg{jobs: jobs}
+		jobs, err := backend.ListJobs()
+		return jobsRefreshedMsg{jobs: jobs, err: err}
+	}
+}
+
+// pollJobsCmd is refreshJobsCmd for the periodic tick. Its result isn't
+// tracked, so the activity spinner doesn't blink on every poll.
+func pollJobsCmd() tea.Cmd {
+	refresh := refreshJobsCmd()
+	return func() tea.Msg {
+		msg := refresh().(jobsRefreshedMsg)
+		msg.polled = true
+		return msg
+	}
+}
+
+// cancelJobCmd cancels a system job in the background, so a stuck spooler
+// can't freeze the UI
+func cancelJobCmd(jobID, opID string) tea.Cmd {
+	return func() tea.Msg {
+		return jobCanceledMsg{jobID: jobID, opID: opID, err: backend.Cancel(jobID)}
 	}
 }
 
 // getSystemPrintJobs retrieves the current print queue from the system
 // Uses lpq which shows job titles (filenames) set via lp -t
-func getSystemPrintJobs() []PrintJob {
+func getSystemPrintJobs() ([]PrintJob, error) {
 	// Add timeout to prevent hanging when print spooler is stuck
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "lpq", "-a")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, "lpq", "-a")
 	if err != nil {
-		return []PrintJob{}
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("lpq timed out after 2 seconds")
+		}
+		return nil, fmt.Errorf("lpq failed: %v", err)
 	}
 
-	var jobs []PrintJob
-	lines := strings.Split(string(output), "\n")
-
-	// lpq output format:
-	// Rank    Owner   Job     File(s)                         Total Size
-	// active  adrian  210     filename.pdf                    155648 bytes
-	// 1st     adrian  212     another.pdf                     1024 bytes
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+	jobs := parseLpqOutput(string(output))
+	for i := range jobs {
+		if isPlaceholderJobName(jobs[i].FileName) {
+			// Not submitted by us with -t; ask CUPS for the document name
+			jobs[i].FileName = getJobFileName(jobs[i].ID)
 		}
+		if jobs[i].FileName == "" {
+			jobs[i].FileName = fmt.Sprintf("Job %s", jobs[i].ID)
+		}
+	}
+	return jobs, nil
+}
 
-		// Skip header line
-		if strings.HasPrefix(line, "Rank") {
-			continue
+// parseLpqOutput parses the job lines of lpq output, skipping headers,
+// status lines and anything malformed:
+//
+//	Rank    Owner   Job     File(s)                         Total Size
+//	active  adrian  210     filename.pdf                    155648 bytes
+//	1st     adrian  212     another.pdf                     1024 bytes
+//
+// Locales translate the rank and the size unit ("1er", "octets"), so lines
+// are recognised by their job ID rather than by those words.
+func parseLpqOutput(output string) []PrintJob {
+	var jobs []PrintJob
+	for _, line := range strings.Split(output, "\n") {
+		if job, ok := parseLpqLine(line); ok {
+			jobs = append(jobs, job)
 		}
+	}
+	return jobs
+}
 
-		// Parse: rank owner job filename size "bytes"
-		parts := strings.Fields(line)
-		if len(parts) < 5 {
-			continue
+// parseLpqLine parses "rank owner job file(s) [size [unit]]". The file name
+// may contain spaces; the size is only taken when it parses as one and is
+// otherwise unknown (-1). lpstat's "printer-jobid owner size date" lines are
+// accepted too, leaving the file name to be looked up.
+func parseLpqLine(line string) (PrintJob, bool) {
+	parts := strings.Fields(line)
+	if len(parts) >= 3 && strings.LastIndex(parts[0], "-") > 0 {
+		// The size check keeps a status line of a printer named like
+		// "HP-4" ("HP-4 is ready") from passing as a job
+		jobID, idOK := parseJobIDField(parts[0])
+		if size, sizeOK := parseJobSize(parts[2]); idOK && sizeOK {
+			return PrintJob{ID: jobID, Owner: parts[1], Size: size, Status: "queued"}, true
 		}
+	}
+	if len(parts) < 4 {
+		return PrintJob{}, false
+	}
+	jobID, ok := parseJobIDField(parts[2])
+	if !ok {
+		// Header ("Rank Owner Job") or a status line ("Office is ready")
+		return PrintJob{}, false
+	}
 
-		rank := parts[0]
-		// Skip if not a job line (active, 1st, 2nd, etc.)
-		if rank != "active" && !strings.HasSuffix(rank, "st") &&
-		   !strings.HasSuffix(rank, "nd") && !strings.HasSuffix(rank, "rd") &&
-		   !strings.HasSuffix(rank, "th") {
-			continue
+	rest := parts[3:]
+	size := int64(-1)
+	if n := len(rest); n > 2 && !startsWithDigit(rest[n-1]) {
+		// "155648 bytes": a number followed by its unit
+		if parsed, ok := parseJobSize(rest[n-2]); ok {
+			size, rest = parsed, rest[:n-2]
+		}
+	} else if n > 1 {
+		// A bare size: "155648" or "1.5M"
+		if parsed, ok := parseJobSize(rest[n-1]); ok {
+			size, rest = parsed, rest[:n-1]
 		}
+	}
 
-		jobNum := parts[2]
+	return PrintJob{
+		ID:       jobID,
+		FileName: strings.Join(rest, " "),
+		Owner:    parts[1],
+		Size:     size,
+		Status:   parts[0],
+	}, true
+}
 
-		// Filename is everything between job number and size
-		// Find the "bytes" suffix to locate size
-		bytesIdx := -1
-		for i := len(parts) - 1; i >= 0; i-- {
-			if parts[i] == "bytes" {
-				bytesIdx = i
-				break
-			}
+// parseJobIDField returns the job number of a job column that is either a
+// bare number ("210") or lpstat's "printer-jobid" form ("Office-210")
+func parseJobIDField(field string) (string, bool) {
+	if dash := strings.LastIndex(field, "-"); dash > 0 {
+		field = field[dash+1:]
+	}
+	if field == "" {
+		return "", false
+	}
+	for _, r := range field {
+		if r < '0' || r > '9' {
+			return "", false
 		}
+	}
+	return field, true
+}
 
-		if bytesIdx < 4 {
-			continue
-		}
+// startsWithDigit reports whether s begins with an ASCII digit
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
 
-		// Size is the field before "bytes"
-		size, _ := strconv.ParseInt(parts[bytesIdx-1], 10, 64)
+// parseJobSize parses a size field as printed by lpq/lpstat: "155648", "1k", "1.5M", "2g".
+// Returns false when the field isn't a size at all (e.g. "unknown").
+func parseJobSize(field string) (int64, bool) {
+	field = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(field)), "b")
+	if field == "" {
+		return 0, false
+	}
 
-		// Filename is fields 3 to (bytesIdx-2)
-		fileName := strings.Join(parts[3:bytesIdx-1], " ")
-		if fileName == "" {
-			fileName = fmt.Sprintf("Job %s", jobNum)
-		}
+	multiplier := int64(1)
+	switch field[len(field)-1] {
+	case 'k':
+		multiplier = 1024
+	case 'm':
+		multiplier = 1024 * 1024
+	case 'g':
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier > 1 {
+		field = field[:len(field)-1]
+	}
 
-		job := PrintJob{
-			ID:       jobNum,
-			FileName: fileName,
-			Size:     size,
-			Status:   rank,
-		}
+	if n, err := strconv.ParseInt(field, 10, 64); err == nil && n >= 0 {
+		return n * multiplier, true
+	}
+	if f, err := strconv.ParseFloat(field, 64); err == nil && f >= 0 {
+		return int64(f * float64(multiplier)), true
+	}
+	return 0, false
+}
+
+// jobNameCache remembers document names looked up for jobs we didn't submit,
+// so each job is only queried once instead of on every refresh
+var jobNameCache sync.Map
 
-		jobs = append(jobs, job)
+// isPlaceholderJobName reports whether lpq showed a generic title instead of a filename
+func isPlaceholderJobName(name string) bool {
+	switch strings.ToLower(name) {
+	case "", "(stdin)", "untitled", "(null)":
+		return true
 	}
+	return strings.HasPrefix(name, "smbprn.")
+}
 
-	return jobs
+// getJobFileName asks CUPS over IPP for the document name of a job
+// Returns "" if the name can't be determined
+func getJobFileName(jobID string) string {
+	if cached, ok := jobNameCache.Load(jobID); ok {
+		return cached.(string)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	uri := fmt.Sprintf("ipp://localhost/jobs/%s", jobID)
+	output, err := runner.Output(ctx, "ipptool", "-tv", uri, "get-job-attributes.test")
+	if err != nil && len(output) == 0 {
+		return ""
+	}
+
+	name := parseIPPJobName(string(output))
+	if name != "" {
+		// Jobs whose attributes weren't readable are asked again next refresh
+		jobNameCache.Store(jobID, name)
+	}
+	return name
 }
 
+// parseIPPJobName extracts the document name from ipptool attribute output
+// Prefers document-name-supplied, falling back to a non-generic job-name:
+// "        document-name-supplied (nameWithoutLanguage) = report.pdf"
+func parseIPPJobName(output string) string {
+	jobName := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		eq := strings.Index(line, " = ")
+		if eq == -1 {
+			continue
+		}
+		attr := strings.Fields(line[:eq])
+		if len(attr) == 0 {
+			continue
+		}
+		value := strings.TrimSpace(line[eq+3:])
+		switch attr[0] {
+		case "document-name-supplied":
+			if !isPlaceholderJobName(value) {
+				return filepath.Base(value)
+			}
+		case "job-name":
+			if !isPlaceholderJobName(value) {
+				jobName = filepath.Base(value)
+			}
+		}
+	}
+	return jobName
+}
 
 // cancelPrintJob cancels a specific print job
 func cancelPrintJob(jobID string) error {
-	cmd := exec.Command("cancel", jobID)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	
-	err := cmd.Run()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, stderr, err := runner.Run(ctx, "cancel", jobID)
 	if err != nil {
-		return fmt.Errorf("failed to cancel job %s: %v - %s", jobID, err, stderr.String())
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("canceling job %s timed out after 5 seconds", jobID)
+		}
+		return fmt.Errorf("failed to cancel job %s: %v - %s", jobID, err, stderr)
 	}
-	
 	return nil
 }
 
@@ -183,15 +405,104 @@ func addToPrintQueue(filePath string) error {
 
 	// Send to printer using lp with -t to set job title (filename)
 	fileName := filepath.Base(filePath)
-	cmd := exec.Command("lp", "-t", fileName, filePath)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
+	_, stderr, err := runner.Run(context.Background(), "lp", "-t", fileName, filePath)
 	if err != nil {
-		return fmt.Errorf("failed to print %s: %v - %s", filePath, err, stderr.String())
+		return fmt.Errorf("failed to print %s: %v - %s", filePath, err, stderr)
+	}
+
+	return nil
+}
+
+// copyToClipboard puts text on the system clipboard using whichever
+// clipboard tool is available for the platform
+func copyToClipboard(text string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		candidates = [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+			{"clip.exe"}, // WSL
+		}
+	}
+
+	for _, argv := range candidates {
+		if _, err := exec.LookPath(argv[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(argv[0], argv[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return fmt.Errorf("no clipboard tool found")
+}
+
+// shellExitedMsg is sent when the shell started with S exits
+type shellExitedMsg struct {
+	err error
+}
+
+// shellCmd suspends the TUI and runs $SHELL (or /bin/sh) in dir, resuming
+// the app when the shell exits. Needs an interactive terminal.
+func shellCmd(dir string) tea.Cmd {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
 	}
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return shellExitedMsg{err: err}
+	})
+}
 
+// flashDuration is how long the screen stays inverted for a flash alert
+const flashDuration = 150 * time.Millisecond
+
+// failureAlertCmd rings the bell and/or flashes the screen, as configured,
+// to draw attention to a failed print
+func failureAlertCmd() tea.Cmd {
+	if !config.BellOnFailure && !config.FlashOnFailure {
+		return nil
+	}
+	return func() tea.Msg {
+		if config.BellOnFailure {
+			fmt.Fprint(os.Stdout, "\a")
+		}
+		if config.FlashOnFailure {
+			// DECSCNM: reverse video for the whole screen, then back
+			fmt.Fprint(os.Stdout, "\x1b[?5h")
+			time.Sleep(flashDuration)
+			fmt.Fprint(os.Stdout, "\x1b[?5l")
+		}
+		return nil
+	}
+}
+
+// openerCommand returns the platform's command for opening target with its
+// default application (a folder opens in the file manager)
+func openerCommand(target string) []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open", target}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", target}
+	default:
+		return []string{"xdg-open", target}
+	}
+}
+
+// openWithDefault starts the platform opener for target without waiting for it
+func openWithDefault(target string) error {
+	argv := openerCommand(target)
+	if err := exec.Command(argv[0], argv[1:]...).Start(); err != nil {
+		return fmt.Errorf("%s: %w", argv[0], err)
+	}
 	return nil
 }
 
@@ -200,9 +511,7 @@ func openFile(filePath string) error {
 	if filePath == "" {
 		return nil
 	}
-	
-	cmd := exec.Command("open", filePath)
-	return cmd.Start()
+	return openWithDefault(filePath)
 }
 
 // openFolder opens the containing folder of a file
@@ -210,8 +519,5 @@ func openFolder(filePath string) error {
 	if filePath == "" {
 		return nil
 	}
-	
-	dir := filepath.Dir(filePath)
-	cmd := exec.Command("open", dir)
-	return cmd.Start()
+	return openWithDefault(filepath.Dir(filePath))
 }
\ No newline at end of file
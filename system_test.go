@@ -0,0 +1,234 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLpqOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []PrintJob
+	}{
+		{
+			name: "english lpq",
+			output: `Office is ready and printing
+Rank    Owner   Job     File(s)                         Total Size
+active  adrian  210     report.pdf                      155648 bytes
+1st     adrian  212     another.pdf                     1024 bytes
+`,
+			want: []PrintJob{
+				{ID: "210", FileName: "report.pdf", Owner: "adrian", Size: 155648, Status: "active"},
+				{ID: "212", FileName: "another.pdf", Owner: "adrian", Size: 1024, Status: "1st"},
+			},
+		},
+		{
+			name: "localized rank and unit",
+			output: `Office est prêt et imprime
+Rang    Propr.  Tâche   Fichier(s)                      Taille totale
+actif   adrian  210     rapport.pdf                     155648 octets
+1er     adrian  211     autre.pdf                       2048 Bytes
+`,
+			want: []PrintJob{
+				{ID: "210", FileName: "rapport.pdf", Owner: "adrian", Size: 155648, Status: "actif"},
+				{ID: "211", FileName: "autre.pdf", Owner: "adrian", Size: 2048, Status: "1er"},
+			},
+		},
+		{
+			name:   "file names with spaces and numbers",
+			output: "2nd     bob     213     Q3 report 2026 final.pdf        4096 bytes\n",
+			want: []PrintJob{
+				{ID: "213", FileName: "Q3 report 2026 final.pdf", Owner: "bob", Size: 4096, Status: "2nd"},
+			},
+		},
+		{
+			name:   "suffixed and bare sizes",
+			output: "3rd  bob  214  (stdin)  1.5M\n4th  bob  215  scan.pdf  12k\n",
+			want: []PrintJob{
+				{ID: "214", FileName: "(stdin)", Owner: "bob", Size: 1572864, Status: "3rd"},
+				{ID: "215", FileName: "scan.pdf", Owner: "bob", Size: 12288, Status: "4th"},
+			},
+		},
+		{
+			name:   "missing or unknown size",
+			output: "5th  bob  216  notes.txt\n6th  bob  217  notes two.txt unknown\n",
+			want: []PrintJob{
+				{ID: "216", FileName: "notes.txt", Owner: "bob", Size: -1, Status: "5th"},
+				{ID: "217", FileName: "notes two.txt unknown", Owner: "bob", Size: -1, Status: "6th"},
+			},
+		},
+		{
+			name:   "printer-jobid in the job column",
+			output: "active  adrian  Office-210  report.pdf  155648 bytes\n",
+			want: []PrintJob{
+				{ID: "210", FileName: "report.pdf", Owner: "adrian", Size: 155648, Status: "active"},
+			},
+		},
+		{
+			name: "lpstat -o with spaces in the date",
+			output: `Office-210              adrian          155648   Thu 15 Oct 2026 10:02:11 AM CEST
+HP-4-211                bob               1024   Thu 15 Oct 2026 10:05:40 AM CEST
+`,
+			want: []PrintJob{
+				{ID: "210", Owner: "adrian", Size: 155648, Status: "queued"},
+				{ID: "211", Owner: "bob", Size: 1024, Status: "queued"},
+			},
+		},
+		{
+			name: "malformed lines are skipped, valid ones kept",
+			output: `HP-4 is ready
+no entries
+1st  adrian  abc  broken.pdf  10 bytes
+active adrian
+2nd  adrian  218  kept.pdf  10 bytes
+`,
+			want: []PrintJob{
+				{ID: "218", FileName: "kept.pdf", Owner: "adrian", Size: 10, Status: "2nd"},
+			},
+		},
+		{
+			name:   "empty queue",
+			output: "Office is ready\nno entries\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLpqOutput(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLpqOutput() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJobIDField(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+		ok    bool
+	}{
+		{"210", "210", true},
+		{"Office-210", "210", true},
+		{"EPSON_ET_2810_Series-216", "216", true},
+		{"HP-4-211", "211", true},
+		{"Job", "", false},
+		{"Office-", "", false},
+		{"Office-abc", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseJobIDField(tt.field)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseJobIDField(%q) = %q, %v; want %q, %v", tt.field, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// ipptoolJobOutput is captured `ipptool -tv ipp://localhost/jobs/42
+// get-job-attributes.test` output for a job printed from another program
+const ipptoolJobOutput = `"/usr/share/cups/ipptool/get-job-attributes.test":
+    Get-Job-Attributes:
+        attributes-charset (charset) = utf-8
+        attributes-natural-language (naturalLanguage) = en
+        job-uri (uri) = ipp://localhost/jobs/42
+        requesting-user-name (nameWithoutLanguage) = adrian
+    Get job attributes                                                   [PASS]
+        RECEIVED: 1106 bytes in response
+        status-code = successful-ok (successful-ok)
+        attributes-charset (charset) = utf-8
+        attributes-natural-language (naturalLanguage) = en-us
+        job-id (integer) = 42
+        job-name (nameWithoutLanguage) = Microsoft Word - Quarterly.docx
+        document-name-supplied (nameWithoutLanguage) = /home/bob/Quarterly report (final).pdf
+        job-originating-user-name (nameWithoutLanguage) = bob
+        job-state (enum) = processing
+        job-printer-uri (uri) = ipp://localhost/printers/Office
+`
+
+func TestParseIPPJobName(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"document name", ipptoolJobOutput, "Quarterly report (final).pdf"},
+		{"job name only", strings.ReplaceAll(ipptoolJobOutput, "document-name-supplied", "x-unrelated"),
+			"Microsoft Word - Quarterly.docx"},
+		{"placeholder document name", strings.ReplaceAll(ipptoolJobOutput,
+			"/home/bob/Quarterly report (final).pdf", "(stdin)"), "Microsoft Word - Quarterly.docx"},
+		{"only placeholders", `        job-name (nameWithoutLanguage) = untitled
+        document-name-supplied (nameWithoutLanguage) = smbprn.00000012 Remote Downlevel Document`, ""},
+		{"no attributes", "ipptool: Unable to connect to \"localhost\" on port 631", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseIPPJobName(tt.output); got != tt.want {
+				t.Errorf("parseIPPJobName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCupsListJobsNamesUntitledJobs(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.stdout["lpq"] = `Office is ready and printing
+Rank    Owner   Job     File(s)                         Total Size
+active  bob     42      (stdin)                         155648 bytes
+`
+	// Like a job whose attributes can't be read yet
+	fake.err["ipptool"] = errFake
+	t.Cleanup(func() { jobNameCache.Delete("42") })
+
+	jobs, err := cupsBackend{}.ListJobs()
+	if err != nil {
+		t.Fatalf("ListJobs() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].FileName != "Job 42" {
+		t.Fatalf("unreadable attributes: jobs = %+v, want Job 42", jobs)
+	}
+
+	// The failed lookup isn't cached, so the name shows up once readable
+	delete(fake.err, "ipptool")
+	fake.stdout["ipptool"] = ipptoolJobOutput
+	if jobs, _ = (cupsBackend{}).ListJobs(); len(jobs) != 1 || jobs[0].FileName != "Quarterly report (final).pdf" {
+		t.Fatalf("ListJobs() = %+v, want the IPP document name", jobs)
+	}
+
+	// Found names are looked up once per job, not on every refresh
+	calls := len(fake.calls)
+	if _, err := (cupsBackend{}).ListJobs(); err != nil {
+		t.Fatalf("third ListJobs() error = %v", err)
+	}
+	if len(fake.calls) != calls+1 {
+		t.Errorf("refresh ran %q, want only lpq", fake.calls[calls:])
+	}
+}
+
+func TestParseJobSize(t *testing.T) {
+	tests := []struct {
+		field string
+		want  int64
+		ok    bool
+	}{
+		{"155648", 155648, true},
+		{"0", 0, true},
+		{"1k", 1024, true},
+		{"1K", 1024, true},
+		{"12kb", 12 * 1024, true},
+		{"1.5M", 1536 * 1024, true},
+		{"2g", 2 * 1024 * 1024 * 1024, true},
+		{" 42 ", 42, true},
+		{"unknown", 0, false},
+		{"", 0, false},
+		{"k", 0, false},
+		{"-5", 0, false},
+		{"1.2.3k", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseJobSize(tt.field)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseJobSize(%q) = %d, %v, want %d, %v", tt.field, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CUPS ships a standard test page; these are its usual locations
+var cupsTestPagePaths = []string{
+	"/usr/share/cups/data/testprint",
+	"/usr/local/share/cups/data/testprint",
+	"/usr/share/cups/data/testprint.ps",
+}
+
+// testPagePath returns CUPS's test page, or writes a minimal PDF to a temp file.
+// isTemp reports whether the caller should remove the file after submitting.
+func testPagePath() (path string, isTemp bool, err error) {
+	for _, candidate := range cupsTestPagePaths {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, false, nil
+		}
+	}
+
+	f, err := os.CreateTemp("", "printer-test-page-*.pdf")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create test page: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buildTestPagePDF(time.Now())); err != nil {
+		os.Remove(f.Name())
+		return "", false, fmt.Errorf("failed to write test page: %v", err)
+	}
+	return f.Name(), true, nil
+}
+
+// buildTestPagePDF builds a one-page A4 PDF with a border and a short message
+func buildTestPagePDF(now time.Time) []byte {
+	stream := fmt.Sprintf("4 w 36 36 523 770 re S\n"+
+		"BT /F1 28 Tf 72 720 Td (Printer Test Page) Tj ET\n"+
+		"BT /F1 14 Tf 72 690 Td (If you can read this, the printer works.) Tj ET\n"+
+		"BT /F1 12 Tf 72 665 Td (Printed %s by printer %s) Tj ET\n",
+		now.Format("2006-01-02 15:04:05"), version)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(stream), stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// testPageCmd submits a test page to printer ("" for the system default) in
+// the background
+func testPageCmd(opID, printer string) tea.Cmd {
+	return func() tea.Msg {
+		path, isTemp, err := testPagePath()
+		if err != nil {
+			return PrintStatusMsg{
+				FileID: opID,
+				Status: StatusFailed,
+				Error:  err,
+			}
+		}
+		if isTemp {
+			defer os.Remove(path)
+		}
+		return submitPrintJobCmd(opID, path, PrintOptions{Printer: printer, Copies: 1})()
+	}
+}
+
+// printTestPage adds a test page operation to the queue and submits it to
+// the selected printer
+func (m *model) printTestPage() tea.Cmd {
+	opID := fmt.Sprintf("test-page-%d", time.Now().UnixNano())
+	printer := m.destination("")
+	m.printOps = append(m.printOps, PrintOperation{
+		ID:        opID,
+		FileName:  "Test page",
+		Printer:   printer,
+		Copies:    1,
+		Status:    StatusSending,
+		StartedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	m.activePane = PaneQueue
+	m.queueSection = SectionActive
+	m.activeCursor = m.getActualJobCount() - 1
+
+	return m.trackCmd(testPageCmd(opID, printer))
+}
+
+// runTestPage prints a test page from the command line (--test-page) to
+// printer ("" for the system default) and reports the result
+func runTestPage(printer string) error {
+	msg := testPageCmd("test-page", printer)().(PrintStatusMsg)
+	if msg.Error != nil {
+		return msg.Error
+	}
+	if msg.Status == StatusSafeMode {
+		fmt.Println("Safe mode: test page not printed")
+		return nil
+	}
+	if msg.SystemJobID != "" {
+		fmt.Printf("Test page sent (job %s)\n", msg.SystemJobID)
+	} else {
+		fmt.Println("Test page sent")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTestPageGoesToSelectedPrinter(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.stdout["lp"] = "request id is Office-9 (1 file(s))\n"
+	m := newTestModel(t)
+	m.selectedPrinter = "Office"
+
+	msg := m.printTestPage()().(PrintStatusMsg)
+	if msg.Error != nil {
+		t.Fatalf("test page failed: %v", msg.Error)
+	}
+	if len(fake.calls) != 1 || !slices.Contains(fake.calls[0], "Office") {
+		t.Fatalf("ran %q, want lp -d Office", fake.calls)
+	}
+	if op := m.printOps[len(m.printOps)-1]; op.Printer != "Office" {
+		t.Errorf("test page operation printer = %q, want Office", op.Printer)
+	}
+}
+
+func TestTestPageDefaultPrinter(t *testing.T) {
+	fake := useFakeRunner(t)
+	m := newTestModel(t)
+	m.selectedPrinter = ""
+
+	m.printTestPage()()
+	if len(fake.calls) != 1 || slices.Contains(fake.calls[0], "-d") {
+		t.Errorf("ran %q, want lp without -d", fake.calls)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxTrackedJobs caps jobs.json so it doesn't grow forever
+const maxTrackedJobs = 500
+
+// JobInfo records a job submitted by this app, keyed by its CUPS job ID
+type JobInfo struct {
+	JobID       string    `json:"job_id"`
+	FilePath    string    `json:"file_path"`
+	FileName    string    `json:"file_name"`
+	Copies      int       `json:"copies"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// JobTracker remembers which file each submitted job came from, so jobs can be
+// opened/matched after a restart. It persists to the XDG data dir when writable
+// and otherwise keeps everything in memory only.
+type JobTracker struct {
+	mu   sync.Mutex
+	path string // jobs.json location, "" when running in memory only
+	jobs map[string]JobInfo
+
+	historyPath string         // history.json location, "" when running in memory only
+	history     []HistoryEntry // Sent prints, oldest first
+}
+
+// dataDir returns the app's XDG data directory ($XDG_DATA_HOME/printer)
+func dataDir() (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(base, "printer"), nil
+}
+
+// ensureWritableDir creates dir if needed and verifies files can be written in it
+func ensureWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(dir, ".write-test-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// NewJobTracker loads the tracker from the data dir. If the data dir isn't
+// writable it returns an in-memory tracker together with the reason.
+func NewJobTracker() (*JobTracker, error) {
+	t := &JobTracker{jobs: make(map[string]JobInfo)}
+
+	dir, err := dataDir()
+	if err == nil {
+		err = ensureWritableDir(dir)
+	}
+	if err != nil {
+		return t, fmt.Errorf("data directory not writable, history won't persist: %v", err)
+	}
+
+	t.path = filepath.Join(dir, "jobs.json")
+	data, err := os.ReadFile(t.path)
+	if err == nil {
+		// A corrupt file just starts a fresh history
+		_ = json.Unmarshal(data, &t.jobs)
+		if t.jobs == nil {
+			t.jobs = make(map[string]JobInfo)
+		}
+	}
+	t.historyPath = filepath.Join(dir, "history.json")
+	t.loadHistory()
+	return t, nil
+}
+
+// IsPersistent reports whether tracked jobs are saved to disk
+func (t *JobTracker) IsPersistent() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.path != ""
+}
+
+// AddJob records a submitted job and saves. If saving fails the tracker
+// switches to memory-only mode and the error is returned once.
+func (t *JobTracker) AddJob(info JobInfo) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.jobs[info.JobID] = info
+	t.prune()
+	return t.save()
+}
+
+// Get returns the tracked info for a CUPS job ID
+func (t *JobTracker) Get(jobID string) (JobInfo, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	info, ok := t.jobs[jobID]
+	return info, ok
+}
+
+// LastPrinted returns when a file was most recently submitted
+func (t *JobTracker) LastPrinted(path string) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var last time.Time
+	for _, info := range t.jobs {
+		if info.FilePath == path && info.SubmittedAt.After(last) {
+			last = info.SubmittedAt
+		}
+	}
+	return last, !last.IsZero()
+}
+
+// Recent returns up to limit tracked jobs, newest first
+func (t *JobTracker) Recent(limit int) []JobInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(t.jobs))
+	for _, info := range t.jobs {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].SubmittedAt.After(infos[j].SubmittedAt)
+	})
+	if len(infos) > limit {
+		infos = infos[:limit]
+	}
+	return infos
+}
+
+// prune drops the oldest jobs beyond maxTrackedJobs (caller holds the lock)
+func (t *JobTracker) prune() {
+	if len(t.jobs) <= maxTrackedJobs {
+		return
+	}
+	infos := make([]JobInfo, 0, len(t.jobs))
+	for _, info := range t.jobs {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].SubmittedAt.Before(infos[j].SubmittedAt)
+	})
+	for _, info := range infos[:len(infos)-maxTrackedJobs] {
+		delete(t.jobs, info.JobID)
+	}
+}
+
+// save writes jobs.json atomically (caller holds the lock)
+func (t *JobTracker) save() error {
+	if t.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(t.jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(t.path, data); err != nil {
+		t.path = ""
+		return fmt.Errorf("cannot save job history, continuing in memory only: %v", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temp file beside path and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
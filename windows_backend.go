@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// windowsBackend drives the Windows print spooler through PowerShell's
+// PrintManagement cmdlets. Files are handed to their application's "Print"
+// or "PrintTo" verb, so raw CUPS options (Extra, Duplex, Pages) don't apply.
+type windowsBackend struct{}
+
+// Submit prints the file once per copy with the registered print verb. The
+// spooler job ID isn't known at this point, so it returns "". Options the
+// print verb can't carry fail the job rather than printing without them.
+func (windowsBackend) Submit(filePath string, opts PrintOptions) (string, error) {
+	if unsupported := windowsUnsupportedOptions(opts); len(unsupported) > 0 {
+		return "", fmt.Errorf("failed to print: %s can't be applied on Windows; clear them and print again",
+			strings.Join(unsupported, ", "))
+	}
+	script := "Start-Process -FilePath " + psQuote(filePath) + " -Verb Print"
+	if opts.Printer != "" {
+		script = "Start-Process -FilePath " + psQuote(filePath) +
+			" -Verb PrintTo -ArgumentList " + psQuote(`"`+opts.Printer+`"`)
+	}
+	copies := opts.Copies
+	if copies < 1 {
+		copies = 1
+	}
+	for i := 0; i < copies; i++ {
+		if _, err := powershell(script, 10*time.Second); err != nil {
+			return "", fmt.Errorf("failed to print: %v", err)
+		}
+	}
+	return "", nil
+}
+
+// windowsUnsupportedOptions names the options set in opts that the print
+// verb can't pass on
+func windowsUnsupportedOptions(opts PrintOptions) []string {
+	var unsupported []string
+	if opts.Duplex != DuplexNone {
+		unsupported = append(unsupported, "duplex")
+	}
+	if opts.Color != ColorAuto {
+		unsupported = append(unsupported, "color mode")
+	}
+	if opts.Pages != "" {
+		unsupported = append(unsupported, "page range "+opts.Pages)
+	}
+	if len(opts.Extra) > 0 {
+		unsupported = append(unsupported, "options "+strings.Join(opts.Extra, " "))
+	}
+	return unsupported
+}
+
+// ListJobs returns the jobs of every printer, with IDs as "PRINTER-ID" like CUPS
+func (windowsBackend) ListJobs() ([]PrintJob, error) {
+	rows, err := powershellCSV("Get-Printer | Get-PrintJob | " +
+		"Select-Object PrinterName,Id,DocumentName,UserName,Size,JobStatus")
+	if err != nil {
+		return nil, err
+	}
+	jobs := []PrintJob{}
+	for _, row := range rows {
+		size, err := strconv.ParseInt(row["Size"], 10, 64)
+		if err != nil {
+			size = -1
+		}
+		status := strings.ToLower(row["JobStatus"])
+		if status == "" || status == "normal" {
+			status = "queued"
+		}
+		jobs = append(jobs, PrintJob{
+			ID:       row["PrinterName"] + "-" + row["Id"],
+			FileName: row["DocumentName"],
+			Owner:    row["UserName"],
+			Size:     size,
+			Status:   status,
+		})
+	}
+	return jobs, nil
+}
+
+// Cancel removes a job given as "PRINTER-ID"
+func (windowsBackend) Cancel(jobID string) error {
+	dash := strings.LastIndex(jobID, "-")
+	if dash <= 0 {
+		return fmt.Errorf("invalid job ID %q", jobID)
+	}
+	printer, id := jobID[:dash], jobID[dash+1:]
+	if _, err := strconv.Atoi(id); err != nil {
+		return fmt.Errorf("invalid job ID %q", jobID)
+	}
+	_, err := powershell("Remove-PrintJob -PrinterName "+psQuote(printer)+" -ID "+id, 5*time.Second)
+	return err
+}
+
+// ListPrinters returns the installed printers; offline ones count as stopped
+func (windowsBackend) ListPrinters() []PrinterInfo {
+	rows, err := powershellCSV("Get-CimInstance Win32_Printer | " +
+		"Select-Object Name,Default,PrinterStatus,WorkOffline")
+	if err != nil {
+		return []PrinterInfo{}
+	}
+	var printers []PrinterInfo
+	for _, row := range rows {
+		info := PrinterInfo{Name: row["Name"], IsDefault: row["Default"] == "True"}
+		switch {
+		case row["WorkOffline"] == "True" || row["PrinterStatus"] == "7":
+			info.Status = "stopped"
+		case row["PrinterStatus"] == "4":
+			info.Status = "printing"
+		default:
+			info.Status = "idle"
+		}
+		printers = append(printers, info)
+	}
+	return printers
+}
+
+// powershell runs a script and returns its standard output
+func powershell(script string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("powershell timed out after %s", timeout)
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%v - %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
+// powershellCSV runs a pipeline through ConvertTo-Csv and returns one map per
+// row, keyed by column name
+func powershellCSV(pipeline string) ([]map[string]string, error) {
+	output, err := powershell(pipeline+" | ConvertTo-Csv -NoTypeInformation", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	header := records[0]
+	var rows []map[string]string
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// psQuote quotes s as a PowerShell single-quoted string
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
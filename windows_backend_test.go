@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWindowsSubmitRejectsUnsupportedOptions(t *testing.T) {
+	tests := []struct {
+		opts PrintOptions
+		want string
+	}{
+		{PrintOptions{Copies: 1, Pages: "2-5"}, "page range 2-5"},
+		{PrintOptions{Copies: 1, Duplex: DuplexLong}, "duplex"},
+		{PrintOptions{Copies: 1, Color: ColorGray}, "color mode"},
+		{PrintOptions{Copies: 1, Extra: []string{"media=A4"}}, "options media=A4"},
+	}
+	for _, tt := range tests {
+		_, err := windowsBackend{}.Submit(`C:\doc.pdf`, tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Submit(%+v) error = %v, want it to name %q", tt.opts, err, tt.want)
+		}
+	}
+}
+
+func TestWindowsUnsupportedOptionsPlainJob(t *testing.T) {
+	if got := windowsUnsupportedOptions(PrintOptions{Printer: "Office", Copies: 3}); len(got) != 0 {
+		t.Errorf("windowsUnsupportedOptions(printer and copies) = %q, want none", got)
+	}
+}